@@ -2,30 +2,67 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
-	"os/exec"
-	"strings"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/aws/fake"
+	"github.com/bsek/s9k/internal/cli"
+	"github.com/bsek/s9k/internal/config"
 	"github.com/bsek/s9k/internal/entrypoint"
 	"github.com/bsek/s9k/internal/github"
 )
 
 func main() {
 	debug := flag.Bool("debug", false, "sets log level to debug")
+	cluster := flag.String("cluster", "", "name of the ECS cluster to show, defaults to the first cluster found")
+	profile := flag.String("profile", "", "AWS profile to use, defaults to AWS_PROFILE or the default profile")
+	region := flag.String("region", "", "AWS region to use, defaults to the region of the profile")
+	endpointURL := flag.String("endpoint-url", "", "custom endpoint URL for all AWS services, e.g. http://localhost:4566 for LocalStack")
+	s3PathStyle := flag.Bool("s3-path-style", false, "use path style addressing for S3 buckets")
+	fakeBackend := flag.Bool("fake", false, "use an in-memory fake AWS backend with demo data instead of AWS")
+	fixtures := flag.String("fixtures", "", "JSON file with fixtures for the fake AWS backend, implies -fake")
+	configFile := flag.String("config", "", "configuration file, defaults to ~/.config/s9k/config.yaml")
 
+	flag.Usage = usage
 	flag.Parse()
 
+	// a mistyped command is reported before the configuration and AWS credentials are loaded
+	if flag.NArg() > 0 && !cli.IsCommand(flag.Arg(0)) {
+		fmt.Fprintf(os.Stderr, "Unknown command %s\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
 	// Default level is info, unless debug flag is present
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	if *debug {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	}
 
+	defaults := config.Default()
+
+	// the fake backend is seeded before the configuration is read, the bucket of the fixtures is the default bucket
+	var fakeFixtures *fake.Fixtures
+	if *fakeBackend || *fixtures != "" {
+		fakeFixtures = loadFixtures(*fixtures)
+		if defaults.S3Bucket == "" {
+			defaults.S3Bucket = fakeFixtures.Bucket
+		}
+	}
+
+	cfg, err := config.Load(*configFile, defaults)
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	config.Set(cfg)
+
 	file, err := os.OpenFile(
-		"/tmp/s9k.log",
+		cfg.LogFile,
 		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
 		0664,
 	)
@@ -35,20 +72,81 @@ func main() {
 
 	log.Logger = zerolog.New(file).With().Timestamp().Logger()
 
-	builder := new(strings.Builder)
+	if fakeFixtures != nil {
+		log.Info().Msg("Using fake AWS backend")
+		fake.NewBackend(*fakeFixtures).Install()
+		run(*cluster)
+		return
+	}
 
-	// retrieve github token from gh tool
-	c := exec.Command("gh", "auth", "token")
-	c.Stdout = builder
-	c.Stderr = builder
-	err = c.Run()
+	endpoints := aws.EndpointsFromEnv()
+	if endpoints.URL == "" {
+		endpoints.URL = cfg.AWS.EndpointURL
+	}
+	if *endpointURL != "" {
+		endpoints.URL = *endpointURL
+	}
+	if *s3PathStyle || cfg.AWS.S3PathStyle {
+		endpoints.S3PathStyle = true
+	}
+	aws.SetEndpoints(endpoints)
 
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to load token from gh tool")
+	if *profile == "" && os.Getenv("AWS_PROFILE") == "" {
+		*profile = cfg.AWS.Profile
+	}
+	if *region == "" && os.Getenv("AWS_REGION") == "" {
+		*region = cfg.AWS.Region
+	}
+
+	if err := aws.Configure(*profile, *region); err != nil {
+		fmt.Println("Failed to load AWS configuration, see log for more information")
+		log.Fatal().Err(err).Msg("Failed to load AWS configuration")
+	}
+
+	// create github client if a token is available, commit messages and ghcr packages are not shown without it
+	if token := github.FindToken(cfg.GitHub.Token); token != "" {
+		github.CreateClient(token)
+	} else {
+		log.Info().Msg("No github token found, github is not used")
+	}
+
+	run(*cluster)
+}
+
+// run executes the subcommand given on the command line, or starts the user interface if there is none
+func run(cluster string) {
+	if flag.NArg() == 0 {
+		entrypoint.Entrypoint(cluster)
+		return
 	}
 
-	// create github client
-	github.CreateClient(builder.String())
+	if err := cli.Run(flag.Args(), cluster); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+
+	fmt.Fprintf(out, "Usage: s9k [flags] [command]\n\nStarts the user interface when no command is given.\n\nCommands:\n")
+	cli.PrintCommands(out)
+	fmt.Fprintf(out, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// loadFixtures returns the fixtures in the given file for the fake backend, or demo data if no file is given
+func loadFixtures(fixturesFile string) *fake.Fixtures {
+	fixtures := fake.DemoFixtures()
+
+	if fixturesFile != "" {
+		var err error
+		fixtures, err = fake.LoadFixtures(fixturesFile)
+		if err != nil {
+			fmt.Printf("Failed to load fixtures for the fake backend: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	entrypoint.Entrypoint()
+	return &fixtures
 }
@@ -68,6 +68,8 @@ func (a *ApiGatewayPage) View() tview.Primitive {
 }
 
 func (a *ApiGatewayPage) Render(accountData *data.AccountData) {
+	a.table.Clear()
+
 	apis := accountData.Apis
 
 	if len(apis) == 0 {
@@ -0,0 +1,38 @@
+package artifacts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+// ECRSource lists images in an ECR repository in the current account and region
+type ECRSource struct {
+	Repository string
+}
+
+func (s *ECRSource) Name() string {
+	return fmt.Sprintf("ecr: %s", s.Repository)
+}
+
+func (s *ECRSource) List() ([]Artifact, error) {
+	packages, err := aws.FetchPackagesFromECR(s.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(packages))
+	for _, p := range packages {
+		artifacts = append(artifacts, Artifact{
+			Version: p.Sha,
+			Commit:  strings.TrimPrefix(p.Sha, "sha-"),
+			Image:   fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:%s", p.RegistryID, aws.CurrentRegion(), p.RepositoryName, p.Sha),
+			Created: p.Created,
+		})
+	}
+
+	sortNewestFirst(artifacts)
+
+	return artifacts, nil
+}
@@ -0,0 +1,38 @@
+package artifacts
+
+import (
+	"fmt"
+
+	"github.com/bsek/s9k/internal/github"
+)
+
+// GHCRSource lists container package versions in the GitHub Container Registry
+type GHCRSource struct {
+	Owner   string
+	Package string
+}
+
+func (s *GHCRSource) Name() string {
+	return fmt.Sprintf("ghcr.io/%s/%s", s.Owner, s.Package)
+}
+
+func (s *GHCRSource) List() ([]Artifact, error) {
+	packages, err := github.FetchPackagesfromGhcr(s.Owner, s.Package)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(packages))
+	for _, p := range packages {
+		artifacts = append(artifacts, Artifact{
+			Version: p.Tag,
+			Commit:  p.Sha,
+			Image:   p.Image,
+			Created: p.Created,
+		})
+	}
+
+	sortNewestFirst(artifacts)
+
+	return artifacts, nil
+}
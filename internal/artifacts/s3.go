@@ -0,0 +1,42 @@
+package artifacts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+// S3Source lists zip files below a prefix in an S3 bucket
+type S3Source struct {
+	Bucket string
+	Prefix string
+}
+
+func (s *S3Source) Name() string {
+	return fmt.Sprintf("s3://%s/%s", s.Bucket, s.Prefix)
+}
+
+func (s *S3Source) List() ([]Artifact, error) {
+	versions, err := aws.FetchAvailableVersions(s.Bucket, s.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(versions))
+	for _, v := range versions {
+		created, _ := time.ParseInLocation(time.DateTime, v.CreatedAt, time.UTC)
+
+		artifacts = append(artifacts, Artifact{
+			Version: strings.TrimPrefix(v.Name, s.Prefix),
+			Bucket:  s.Bucket,
+			Key:     v.Name,
+			Created: created,
+		})
+	}
+
+	sortNewestFirst(artifacts)
+
+	return artifacts, nil
+}
@@ -0,0 +1,69 @@
+package artifacts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bsek/s9k/internal/config"
+)
+
+// Artifact is a deployable version of a service or lambda function
+type Artifact struct {
+	// Version is the image tag or file name shown to the user
+	Version string
+	// Commit is the (short) sha of the commit the artifact was built from, empty if unknown
+	Commit string
+	// Image is the full image uri for container images
+	Image string
+	// Bucket and Key locate zip files in S3
+	Bucket  string
+	Key     string
+	Created time.Time
+}
+
+// Source lists the deployable artifacts of a single service or function
+type Source interface {
+	// Name describes the source, e.g. the registry and repository
+	Name() string
+	// List returns the available artifacts, newest first
+	List() ([]Artifact, error)
+}
+
+// ForService returns the artifact source configured for the given ECS service, ECR unless configured otherwise
+func ForService(clusterName, serviceName string) (Source, error) {
+	return fromSettings(config.ForService(clusterName, serviceName), config.SourceECR)
+}
+
+// ForFunction returns the artifact source configured for the given lambda function, S3 unless configured otherwise
+func ForFunction(functionName string) (Source, error) {
+	return fromSettings(config.ForFunction(functionName), config.SourceS3)
+}
+
+func fromSettings(settings config.Service, defaultSource string) (Source, error) {
+	source := settings.ArtifactSource
+	if source == "" {
+		source = defaultSource
+	}
+
+	switch source {
+	case config.SourceECR:
+		return &ECRSource{Repository: settings.ECRRepositoryName()}, nil
+	case config.SourceGHCR:
+		return &GHCRSource{Owner: settings.GitHub.PackagesOwner, Package: settings.GHCRPackageName()}, nil
+	case config.SourceS3:
+		if settings.S3Bucket == "" {
+			return nil, fmt.Errorf("no s3 bucket configured, set s3Bucket in the configuration file or S3_DEPLOYMENT_BUCKET_NAME")
+		}
+		return &S3Source{Bucket: settings.S3Bucket, Prefix: settings.S3KeyPrefix()}, nil
+	}
+
+	return nil, fmt.Errorf("unknown artifact source %s", source)
+}
+
+// sortNewestFirst sorts the artifacts by creation time, newest first
+func sortNewestFirst(artifacts []Artifact) {
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		return artifacts[i].Created.After(artifacts[j].Created)
+	})
+}
@@ -0,0 +1,96 @@
+package artifacts
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bsek/s9k/internal/aws/fake"
+	"github.com/bsek/s9k/internal/config"
+)
+
+// useConfig makes the package read the configuration until the end of the test
+func useConfig(t *testing.T, c *config.Config) {
+	t.Helper()
+
+	previous := config.Get()
+	config.Set(c)
+	t.Cleanup(func() { config.Set(previous) })
+}
+
+func TestForService(t *testing.T) {
+	c := config.Default()
+	c.Clusters = map[string]config.Cluster{
+		"prod": {Services: map[string]config.Settings{"web": {ArtifactSource: config.SourceGHCR}}},
+	}
+	useConfig(t, c)
+
+	source, err := ForService("prod", "api")
+	if err != nil {
+		t.Fatalf("ForService() failed: %v", err)
+	}
+	if ecr, ok := source.(*ECRSource); !ok || ecr.Repository != "prod-api" {
+		t.Errorf("got source %#v, want ecr repository prod-api", source)
+	}
+
+	source, err = ForService("prod", "web")
+	if err != nil {
+		t.Fatalf("ForService() failed: %v", err)
+	}
+	if ghcr, ok := source.(*GHCRSource); !ok || ghcr.Package != "web" {
+		t.Errorf("got source %#v, want ghcr package web", source)
+	}
+}
+
+func TestForFunction(t *testing.T) {
+	c := config.Default()
+	c.S3Bucket = ""
+	useConfig(t, c)
+
+	if _, err := ForFunction("email-sender"); err == nil {
+		t.Error("ForFunction() without a bucket succeeded")
+	}
+
+	c.S3Bucket = "deployments"
+	source, err := ForFunction("email-sender")
+	if err != nil {
+		t.Fatalf("ForFunction() failed: %v", err)
+	}
+	if got := source.Name(); got != "s3://deployments/email-sender/" {
+		t.Errorf("got source %s, want s3://deployments/email-sender/", got)
+	}
+}
+
+func TestListNewestFirst(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	ecr, err := (&ECRSource{Repository: "dev-api"}).List()
+	if err != nil {
+		t.Fatalf("listing ecr failed: %v", err)
+	}
+	if len(ecr) == 0 || ecr[0].Version != "sha-4f1c2ab" || ecr[0].Commit != "4f1c2ab" {
+		t.Errorf("got ecr artifacts %v, want sha-4f1c2ab first", ecr)
+	}
+
+	s3, err := (&S3Source{Bucket: "s9k-deployments", Prefix: "email-sender/"}).List()
+	if err != nil {
+		t.Fatalf("listing s3 failed: %v", err)
+	}
+	versions := make([]string, 0, len(s3))
+	for _, artifact := range s3 {
+		versions = append(versions, artifact.Version)
+		if artifact.Key != "email-sender/"+artifact.Version {
+			t.Errorf("got key %s for version %s", artifact.Key, artifact.Version)
+		}
+	}
+	if want := []string{"v1.0.0.zip", "v1.1.0.zip", "v1.2.0.zip"}; !slices.Equal(versions, want) {
+		t.Errorf("got s3 versions %v, want %v", versions, want)
+	}
+}
+
+func TestListUnknownBucket(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	if _, err := (&S3Source{Bucket: "other", Prefix: "email-sender/"}).List(); err == nil {
+		t.Error("listing a bucket the fake backend does not serve succeeded")
+	}
+}
@@ -0,0 +1,205 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/oslokommune/common-lib-go/aws/awsapigateway"
+	"github.com/oslokommune/common-lib-go/aws/awsapigatewayv2"
+	awscloudwatch "github.com/oslokommune/common-lib-go/aws/awscloudwatch"
+	"github.com/oslokommune/common-lib-go/aws/awsecr"
+	"github.com/oslokommune/common-lib-go/aws/awsecs"
+	"github.com/oslokommune/common-lib-go/aws/awslambda"
+	"github.com/oslokommune/common-lib-go/aws/awss3"
+)
+
+// ECSProvider describes the ECS operations used by s9k
+type ECSProvider interface {
+	awsecs.ECSServiceApi
+	ExecuteCommand(ctx context.Context, params *ecs.ExecuteCommandInput, optFns ...func(*ecs.Options)) (*ecs.ExecuteCommandOutput, error)
+	ListTaskDefinitions(ctx context.Context, params *ecs.ListTaskDefinitionsInput, optFns ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error)
+	UntagResource(ctx context.Context, params *ecs.UntagResourceInput, optFns ...func(*ecs.Options)) (*ecs.UntagResourceOutput, error)
+}
+
+// ECRProvider describes the ECR operations used by s9k
+type ECRProvider interface {
+	awsecr.DescribeImagesAPI
+}
+
+// LambdaProvider describes the Lambda operations used by s9k
+type LambdaProvider interface {
+	awslambda.ListFunctionsApi
+	awslambda.GetFunctionApi
+	awslambda.GetFunctionConfigurationApi
+	awslambda.UpdateFunctionCodeApi
+	awslambda.UpdateFunctionConfigurationApi
+	awslambda.TagResourceApi
+	ListVersionsByFunction(ctx context.Context, params *lambda.ListVersionsByFunctionInput, optFns ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error)
+	ListAliases(ctx context.Context, params *lambda.ListAliasesInput, optFns ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error)
+	PublishVersion(ctx context.Context, params *lambda.PublishVersionInput, optFns ...func(*lambda.Options)) (*lambda.PublishVersionOutput, error)
+	UpdateAlias(ctx context.Context, params *lambda.UpdateAliasInput, optFns ...func(*lambda.Options)) (*lambda.UpdateAliasOutput, error)
+	Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error)
+	UntagResource(ctx context.Context, params *lambda.UntagResourceInput, optFns ...func(*lambda.Options)) (*lambda.UntagResourceOutput, error)
+	PutFunctionConcurrency(ctx context.Context, params *lambda.PutFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error)
+	DeleteFunctionConcurrency(ctx context.Context, params *lambda.DeleteFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error)
+	ListProvisionedConcurrencyConfigs(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error)
+	PutProvisionedConcurrencyConfig(ctx context.Context, params *lambda.PutProvisionedConcurrencyConfigInput, optFns ...func(*lambda.Options)) (*lambda.PutProvisionedConcurrencyConfigOutput, error)
+	DeleteProvisionedConcurrencyConfig(ctx context.Context, params *lambda.DeleteProvisionedConcurrencyConfigInput, optFns ...func(*lambda.Options)) (*lambda.DeleteProvisionedConcurrencyConfigOutput, error)
+}
+
+// S3Provider describes the S3 operations used by s9k
+type S3Provider interface {
+	awss3.ListObjectsV2API
+}
+
+// SSMProvider describes the Systems Manager Parameter Store operations used by s9k
+type SSMProvider interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	PutParameter(ctx context.Context, params *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
+	GetParameterHistory(ctx context.Context, params *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error)
+}
+
+// STSProvider describes the STS operations used by s9k
+type STSProvider interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// CloudWatchProvider describes the CloudWatch metrics operations used by s9k
+type CloudWatchProvider interface {
+	awscloudwatch.GetMetricDataApi
+}
+
+// CloudWatchLogsProvider describes the CloudWatch Logs operations used by s9k. Live tail is exposed as an event
+// stream directly, since the stream in the StartLiveTail output can only be set by the SDK itself
+type CloudWatchLogsProvider interface {
+	awscloudwatch.DescribeLogStreamsApi
+	awscloudwatch.GetLogEventsApi
+	FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	TailLogs(ctx context.Context, params *cloudwatchlogs.StartLiveTailInput) (*cloudwatchlogs.StartLiveTailEventStream, error)
+}
+
+// APIGatewayProvider describes the API Gateway (REST apis) operations used by s9k
+type APIGatewayProvider interface {
+	awsapigateway.ApiGatewayGetRestApisApi
+}
+
+// APIGatewayV2Provider describes the API Gateway v2 (HTTP apis) operations used by s9k
+type APIGatewayV2Provider interface {
+	awsapigatewayv2.ApiGatewayv2GetApiMappingApi
+	awsapigatewayv2.ApiGatewayv2GetDomainNames
+	awsapigatewayv2.ApiGatewayv2GetApisApi
+	awsapigatewayv2.ApiGatewayv2GetStageApi
+}
+
+// ApplicationAutoScalingProvider describes the Application Auto Scaling operations used by s9k
+type ApplicationAutoScalingProvider interface {
+	DescribeScalableTargets(ctx context.Context, params *applicationautoscaling.DescribeScalableTargetsInput, optFns ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalableTargetsOutput, error)
+	RegisterScalableTarget(ctx context.Context, params *applicationautoscaling.RegisterScalableTargetInput, optFns ...func(*applicationautoscaling.Options)) (*applicationautoscaling.RegisterScalableTargetOutput, error)
+}
+
+// Clients holds the service clients used by the functions in this package
+type Clients struct {
+	ECS            ECSProvider
+	ECR            ECRProvider
+	Lambda         LambdaProvider
+	S3             S3Provider
+	SSM            SSMProvider
+	STS            STSProvider
+	CloudWatch     CloudWatchProvider
+	CloudWatchLogs CloudWatchLogsProvider
+	APIGateway     APIGatewayProvider
+	APIGatewayV2   APIGatewayV2Provider
+	AutoScaling    ApplicationAutoScalingProvider
+}
+
+// logsClient adapts the SDK CloudWatch Logs client to the CloudWatchLogsProvider interface
+type logsClient struct {
+	*cloudwatchlogs.Client
+}
+
+func (c logsClient) TailLogs(ctx context.Context, params *cloudwatchlogs.StartLiveTailInput) (*cloudwatchlogs.StartLiveTailEventStream, error) {
+	output, err := c.StartLiveTail(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.GetStream(), nil
+}
+
+// NewClients creates SDK clients for every service from the given configuration. Endpoints that are set
+// override the default AWS endpoints
+func NewClients(cfg aws.Config, e Endpoints) Clients {
+	return Clients{
+		ECS: ecs.NewFromConfig(cfg, func(o *ecs.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceECS)
+		}),
+		ECR: ecr.NewFromConfig(cfg, func(o *ecr.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceECR)
+		}),
+		Lambda: lambda.NewFromConfig(cfg, func(o *lambda.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceLambda)
+		}),
+		S3: s3.NewFromConfig(cfg, func(o *s3.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceS3)
+			o.UsePathStyle = o.UsePathStyle || e.S3PathStyle
+		}),
+		SSM: ssm.NewFromConfig(cfg, func(o *ssm.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceSSM)
+		}),
+		STS: sts.NewFromConfig(cfg, func(o *sts.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceSTS)
+		}),
+		CloudWatch: cloudwatch.NewFromConfig(cfg, func(o *cloudwatch.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceCloudWatch)
+		}),
+		CloudWatchLogs: logsClient{cloudwatchlogs.NewFromConfig(cfg, func(o *cloudwatchlogs.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceLogs)
+		})},
+		APIGateway: apigateway.NewFromConfig(cfg, func(o *apigateway.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceAPIGateway)
+		}),
+		APIGatewayV2: apigatewayv2.NewFromConfig(cfg, func(o *apigatewayv2.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceAPIGatewayV2)
+		}),
+		AutoScaling: applicationautoscaling.NewFromConfig(cfg, func(o *applicationautoscaling.Options) {
+			setEndpoint(&o.BaseEndpoint, e, ServiceAutoScaling)
+		}),
+	}
+}
+
+// setEndpoint sets the client base endpoint if an endpoint is configured for the service
+func setEndpoint(baseEndpoint **string, e Endpoints, service string) {
+	if url := e.URLFor(service); url != "" {
+		*baseEndpoint = aws.String(url)
+	}
+}
+
+// UseClients replaces the clients used by this package, for instance with a fake backend. Profile and region are
+// only used for display and for constructing ARNs
+func UseClients(clients Clients, profile, region string) {
+	ecsClient = clients.ECS
+	ecrClient = clients.ECR
+	lambdaClient = clients.Lambda
+	s3Client = clients.S3
+	ssmClient = clients.SSM
+	stsClient = clients.STS
+	cloudwatchClient = clients.CloudWatch
+	cloudwatchLogsClient = clients.CloudWatchLogs
+	apigatewayClient = clients.APIGateway
+	apigatewayv2Client = clients.APIGatewayV2
+	autoScalingClient = clients.AutoScaling
+
+	currentProfile = profile
+	currentRegion = region
+}
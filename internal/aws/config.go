@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/rs/zerolog/log"
+)
+
+const DEFAULT_PROFILE = "default"
+
+// Regions lists the commonly used AWS regions that can be selected in the application
+var Regions = []string{
+	"eu-north-1",
+	"eu-west-1",
+	"eu-west-2",
+	"eu-west-3",
+	"eu-central-1",
+	"eu-central-2",
+	"eu-south-1",
+	"us-east-1",
+	"us-east-2",
+	"us-west-1",
+	"us-west-2",
+	"ca-central-1",
+	"sa-east-1",
+	"ap-northeast-1",
+	"ap-northeast-2",
+	"ap-south-1",
+	"ap-southeast-1",
+	"ap-southeast-2",
+}
+
+// Configure loads the AWS configuration for the given shared config profile and region and (re)creates every
+// service client from it. Empty values fall back to the default configuration chain (environment variables,
+// AWS_PROFILE, the region configured for the profile etc.)
+func Configure(profile, region string) error {
+	options := make([]func(*config.LoadOptions) error, 0)
+
+	if profile != "" {
+		options = append(options, config.WithSharedConfigProfile(profile))
+	}
+
+	if region != "" {
+		options = append(options, config.WithRegion(region))
+	}
+
+	if endpoints.HasStaticCredentials() {
+		options = append(options, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(endpoints.AccessKeyID, endpoints.SecretAccessKey, "")))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), options...)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to load AWS configuration for profile [%s] and region [%s]", profile, region)
+		return err
+	}
+
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = DEFAULT_PROFILE
+	}
+
+	UseClients(NewClients(cfg, endpoints), profile, cfg.Region)
+
+	return nil
+}
+
+// CurrentProfile returns the name of the shared config profile the clients are configured with
+func CurrentProfile() string {
+	return currentProfile
+}
+
+// CurrentRegion returns the region the clients are configured with
+func CurrentRegion() string {
+	return currentRegion
+}
+
+// ListProfiles returns the names of all profiles found in the shared config and credentials files, sorted by name
+func ListProfiles() ([]string, error) {
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+
+	names := make(map[string]bool)
+
+	configProfiles, err := readProfileSections(configFile, true)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialProfiles, err := readProfileSections(credentialsFile, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range append(configProfiles, credentialProfiles...) {
+		names[v] = true
+	}
+
+	profiles := make([]string, 0, len(names))
+	for k := range names {
+		profiles = append(profiles, k)
+	}
+	sort.Strings(profiles)
+
+	return profiles, nil
+}
+
+// readProfileSections reads the section names from an ini formatted shared config or credentials file. In the
+// config file, profiles other than default are prefixed with "profile ", other sections (sso-session, services)
+// are ignored. A missing file is not an error
+func readProfileSections(filename string, configFile bool) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := make([]string, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+
+		section := strings.TrimSpace(line[1 : len(line)-1])
+
+		if configFile && section != DEFAULT_PROFILE {
+			if !strings.HasPrefix(section, "profile ") {
+				continue
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(section, "profile "))
+		}
+
+		if section != "" {
+			profiles = append(profiles, section)
+		}
+	}
+
+	return profiles, scanner.Err()
+}
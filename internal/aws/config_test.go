@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestListProfiles(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", writeFile(t, "config", `
+[default]
+region = eu-north-1
+
+[profile dev]
+sso_session = company
+
+[ profile prod ]
+region = eu-west-1
+
+[sso-session company]
+sso_region = eu-north-1
+
+[services local]
+`))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", writeFile(t, "credentials", `
+[default]
+aws_access_key_id = AKIAEXAMPLE
+
+[ci]
+aws_access_key_id = AKIAEXAMPLE
+`))
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() failed: %v", err)
+	}
+
+	if want := []string{"ci", "default", "dev", "prod"}; !slices.Equal(profiles, want) {
+		t.Errorf("got profiles %v, want %v", profiles, want)
+	}
+}
+
+func TestListProfilesWithoutFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() failed: %v", err)
+	}
+	if len(profiles) != 0 {
+		t.Errorf("got profiles %v, want none", profiles)
+	}
+}
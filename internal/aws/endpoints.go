@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Service names used as keys for per service endpoints
+const (
+	ServiceECS          = "ecs"
+	ServiceECR          = "ecr"
+	ServiceLambda       = "lambda"
+	ServiceS3           = "s3"
+	ServiceSSM          = "ssm"
+	ServiceSTS          = "sts"
+	ServiceCloudWatch   = "cloudwatch"
+	ServiceLogs         = "logs"
+	ServiceAPIGateway   = "apigateway"
+	ServiceAPIGatewayV2 = "apigatewayv2"
+	ServiceAutoScaling  = "applicationautoscaling"
+)
+
+// Services lists the names of all services s9k creates clients for
+var Services = []string{ServiceECS, ServiceECR, ServiceLambda, ServiceS3, ServiceSSM, ServiceSTS, ServiceCloudWatch, ServiceLogs, ServiceAPIGateway, ServiceAPIGatewayV2, ServiceAutoScaling}
+
+// Endpoints overrides the endpoints the service clients connect to, for instance to run against LocalStack or
+// another local AWS stand-in. Zero values keep the default AWS behaviour
+type Endpoints struct {
+	// URL is used for every service without its own endpoint
+	URL string
+	// Services maps a service name (see Services) to an endpoint URL
+	Services map[string]string
+	// S3PathStyle addresses buckets as part of the path instead of the host name
+	S3PathStyle bool
+	// AccessKeyID and SecretAccessKey are used as static credentials when both are set
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+var endpoints Endpoints
+
+// SetEndpoints sets the endpoints used the next time the clients are configured
+func SetEndpoints(e Endpoints) {
+	endpoints = e
+}
+
+// EndpointsFromEnv reads endpoint configuration from S9K_ENDPOINT_URL, S9K_ENDPOINT_URL_<SERVICE> (e.g.
+// S9K_ENDPOINT_URL_LAMBDA), S9K_S3_PATH_STYLE, S9K_ACCESS_KEY_ID and S9K_SECRET_ACCESS_KEY
+func EndpointsFromEnv() Endpoints {
+	e := Endpoints{
+		URL:             os.Getenv("S9K_ENDPOINT_URL"),
+		Services:        make(map[string]string),
+		AccessKeyID:     os.Getenv("S9K_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S9K_SECRET_ACCESS_KEY"),
+	}
+
+	for _, service := range Services {
+		if url := os.Getenv("S9K_ENDPOINT_URL_" + strings.ToUpper(service)); url != "" {
+			e.Services[service] = url
+		}
+	}
+
+	if value, found := os.LookupEnv("S9K_S3_PATH_STYLE"); found {
+		e.S3PathStyle, _ = strconv.ParseBool(value)
+	}
+
+	return e
+}
+
+// URLFor returns the endpoint to use for the given service, or an empty string to use the AWS default
+func (e Endpoints) URLFor(service string) string {
+	if url, found := e.Services[service]; found && url != "" {
+		return url
+	}
+	return e.URL
+}
+
+// HasStaticCredentials returns true if static credentials are configured
+func (e Endpoints) HasStaticCredentials() bool {
+	return e.AccessKeyID != "" && e.SecretAccessKey != ""
+}
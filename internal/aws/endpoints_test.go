@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestEndpointsFromEnv(t *testing.T) {
+	t.Setenv("S9K_ENDPOINT_URL", "http://localhost:4566")
+	t.Setenv("S9K_ENDPOINT_URL_LAMBDA", "http://localhost:9001")
+	t.Setenv("S9K_ENDPOINT_URL_S3", "")
+	t.Setenv("S9K_S3_PATH_STYLE", "true")
+	t.Setenv("S9K_ACCESS_KEY_ID", "test")
+	t.Setenv("S9K_SECRET_ACCESS_KEY", "secret")
+
+	e := EndpointsFromEnv()
+
+	if e.URL != "http://localhost:4566" {
+		t.Errorf("got url %q", e.URL)
+	}
+	if want := map[string]string{ServiceLambda: "http://localhost:9001"}; !maps.Equal(e.Services, want) {
+		t.Errorf("got service endpoints %v, want %v", e.Services, want)
+	}
+	if !e.S3PathStyle {
+		t.Error("got virtual hosted style for s3")
+	}
+	if !e.HasStaticCredentials() {
+		t.Error("got no static credentials")
+	}
+}
+
+func TestEndpointsFromEmptyEnv(t *testing.T) {
+	for _, name := range []string{"S9K_ENDPOINT_URL", "S9K_S3_PATH_STYLE", "S9K_ACCESS_KEY_ID", "S9K_SECRET_ACCESS_KEY"} {
+		t.Setenv(name, "")
+	}
+	for _, service := range Services {
+		t.Setenv("S9K_ENDPOINT_URL_"+strings.ToUpper(service), "")
+	}
+
+	e := EndpointsFromEnv()
+
+	if e.URL != "" || len(e.Services) != 0 || e.S3PathStyle || e.HasStaticCredentials() {
+		t.Errorf("got %+v, want the AWS defaults", e)
+	}
+}
+
+func TestURLFor(t *testing.T) {
+	e := Endpoints{
+		URL:      "http://localhost:4566",
+		Services: map[string]string{ServiceLambda: "http://localhost:9001", ServiceS3: ""},
+	}
+
+	tests := map[string]string{
+		ServiceLambda: "http://localhost:9001",
+		ServiceS3:     "http://localhost:4566",
+		ServiceECS:    "http://localhost:4566",
+	}
+	for service, want := range tests {
+		if got := e.URLFor(service); got != want {
+			t.Errorf("URLFor(%s) = %q, want %q", service, got, want)
+		}
+	}
+
+	if got := (Endpoints{}).URLFor(ServiceECS); got != "" {
+		t.Errorf("got %q without endpoints, want the AWS default", got)
+	}
+}
+
+func TestHasStaticCredentials(t *testing.T) {
+	if (Endpoints{AccessKeyID: "test"}).HasStaticCredentials() {
+		t.Error("an access key without secret is used as static credentials")
+	}
+}
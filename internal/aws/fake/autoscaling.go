@@ -0,0 +1,72 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+	"github.com/samber/lo"
+)
+
+type autoScalingClient struct {
+	b *Backend
+}
+
+func (c *autoScalingClient) DescribeScalableTargets(_ context.Context, params *applicationautoscaling.DescribeScalableTargetsInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.DescribeScalableTargetsOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	targets := make([]autoscalingtypes.ScalableTarget, 0)
+	for _, v := range c.b.fixtures.ScalableTargets {
+		if v.ServiceNamespace != params.ServiceNamespace {
+			continue
+		}
+		if params.ScalableDimension != "" && v.ScalableDimension != params.ScalableDimension {
+			continue
+		}
+		if len(params.ResourceIds) > 0 && !lo.Contains(params.ResourceIds, *v.ResourceId) {
+			continue
+		}
+		targets = append(targets, v)
+	}
+
+	return &applicationautoscaling.DescribeScalableTargetsOutput{ScalableTargets: targets}, nil
+}
+
+// RegisterScalableTarget updates the capacity of a registered target. Like in AWS, a new target needs both the
+// minimum and maximum capacity
+func (c *autoScalingClient) RegisterScalableTarget(_ context.Context, params *applicationautoscaling.RegisterScalableTargetInput, _ ...func(*applicationautoscaling.Options)) (*applicationautoscaling.RegisterScalableTargetOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	for i, v := range c.b.fixtures.ScalableTargets {
+		if v.ServiceNamespace != params.ServiceNamespace || v.ScalableDimension != params.ScalableDimension || *v.ResourceId != *params.ResourceId {
+			continue
+		}
+
+		target := &c.b.fixtures.ScalableTargets[i]
+		if params.MinCapacity != nil {
+			target.MinCapacity = params.MinCapacity
+		}
+		if params.MaxCapacity != nil {
+			target.MaxCapacity = params.MaxCapacity
+		}
+		return &applicationautoscaling.RegisterScalableTargetOutput{}, nil
+	}
+
+	if params.MinCapacity == nil || params.MaxCapacity == nil {
+		return nil, &autoscalingtypes.ValidationException{Message: awssdk.String(fmt.Sprintf("No scalable target registered for %s", *params.ResourceId))}
+	}
+
+	c.b.fixtures.ScalableTargets = append(c.b.fixtures.ScalableTargets, autoscalingtypes.ScalableTarget{
+		ServiceNamespace:  params.ServiceNamespace,
+		ScalableDimension: params.ScalableDimension,
+		ResourceId:        params.ResourceId,
+		MinCapacity:       params.MinCapacity,
+		MaxCapacity:       params.MaxCapacity,
+	})
+
+	return &applicationautoscaling.RegisterScalableTargetOutput{}, nil
+}
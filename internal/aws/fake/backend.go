@@ -0,0 +1,173 @@
+// Package fake provides an in-memory implementation of the AWS services used by s9k. It is seeded with fixtures
+// and lets the application run without an AWS account, and code depending on the aws package be tested offline.
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+var errNotFound = errors.New("resource not found in fake backend")
+
+// Fixtures is the data the fake backend is seeded with. Resources are related to each other the same way they
+// are in AWS, e.g. services refer to their cluster with ClusterArn and tasks to their service with Group
+type Fixtures struct {
+	AccountId       string
+	Region          string
+	Profile         string
+	Clusters        []ecstypes.Cluster
+	Services        []ecstypes.Service
+	Tasks           []ecstypes.Task
+	TaskDefinitions []ecstypes.TaskDefinition
+	Images          []ecrtypes.ImageDetail
+	Functions       []lambdatypes.FunctionConfiguration
+	FunctionTags    map[string]map[string]string
+	// FunctionVersions holds the published versions of each function, oldest first
+	FunctionVersions map[string][]lambdatypes.FunctionConfiguration
+	FunctionAliases  map[string][]lambdatypes.AliasConfiguration
+	// ReservedConcurrency holds the reserved concurrency of functions that have one
+	ReservedConcurrency    map[string]int32
+	ProvisionedConcurrency map[string][]lambdatypes.ProvisionedConcurrencyConfigListItem
+	// Bucket is the S3 bucket holding Objects, it is the default s3Bucket of the configuration when the fake
+	// backend is used
+	Bucket     string
+	Objects    []s3types.Object
+	Parameters map[string]string
+	// ParameterHistory holds the previous values of parameters, oldest first. Parameters without history get a
+	// single version with their current value
+	ParameterHistory map[string][]ssmtypes.ParameterHistory
+	RestApis         []apigatewaytypes.RestApi
+	HttpApis         []apigatewayv2types.Api
+	DomainNames      []apigatewayv2types.DomainName
+	ApiMappings      map[string][]apigatewayv2types.ApiMapping
+	LogMessages      []string
+	ScalableTargets  []autoscalingtypes.ScalableTarget
+}
+
+// Backend is an in-memory AWS backend. All changes made through the clients are applied to the fixtures, so
+// for instance deploying an image to a service is visible when the service is described again
+type Backend struct {
+	mu       sync.Mutex
+	fixtures Fixtures
+	tags     map[string]map[string]string
+	// invocations counts the lambda invocations, for request ids
+	invocations int
+	// functionUpdates holds when the update in progress of each function completes
+	functionUpdates map[string]time.Time
+}
+
+// NewBackend creates a backend seeded with the given fixtures
+func NewBackend(fixtures Fixtures) *Backend {
+	if fixtures.FunctionTags == nil {
+		fixtures.FunctionTags = make(map[string]map[string]string)
+	}
+	if fixtures.FunctionVersions == nil {
+		fixtures.FunctionVersions = make(map[string][]lambdatypes.FunctionConfiguration)
+	}
+	if fixtures.FunctionAliases == nil {
+		fixtures.FunctionAliases = make(map[string][]lambdatypes.AliasConfiguration)
+	}
+	if fixtures.ReservedConcurrency == nil {
+		fixtures.ReservedConcurrency = make(map[string]int32)
+	}
+	if fixtures.ProvisionedConcurrency == nil {
+		fixtures.ProvisionedConcurrency = make(map[string][]lambdatypes.ProvisionedConcurrencyConfigListItem)
+	}
+	if fixtures.Parameters == nil {
+		fixtures.Parameters = make(map[string]string)
+	}
+	if fixtures.ApiMappings == nil {
+		fixtures.ApiMappings = make(map[string][]apigatewayv2types.ApiMapping)
+	}
+	if fixtures.ParameterHistory == nil {
+		fixtures.ParameterHistory = make(map[string][]ssmtypes.ParameterHistory)
+	}
+	for name, value := range fixtures.Parameters {
+		if len(fixtures.ParameterHistory[name]) == 0 {
+			fixtures.ParameterHistory[name] = []ssmtypes.ParameterHistory{
+				{Name: awssdk.String(name), Value: awssdk.String(value), Version: 1},
+			}
+		}
+	}
+
+	return &Backend{
+		fixtures:        fixtures,
+		tags:            make(map[string]map[string]string),
+		functionUpdates: make(map[string]time.Time),
+	}
+}
+
+// LoadFixtures reads fixtures from a JSON file. The file uses the field names of the AWS SDK types
+func LoadFixtures(filename string) (Fixtures, error) {
+	var fixtures Fixtures
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return fixtures, err
+	}
+
+	if err := json.Unmarshal(content, &fixtures); err != nil {
+		return fixtures, fmt.Errorf("failed to parse fixtures file %s: %w", filename, err)
+	}
+
+	return fixtures, nil
+}
+
+// Clients returns service clients backed by this backend
+func (b *Backend) Clients() aws.Clients {
+	return aws.Clients{
+		ECS:            &ecsClient{b},
+		ECR:            &ecrClient{b},
+		Lambda:         &lambdaClient{b},
+		S3:             &s3Client{b},
+		SSM:            &ssmClient{b},
+		STS:            &stsClient{b},
+		CloudWatch:     &cloudwatchClient{b},
+		CloudWatchLogs: &logsClient{b},
+		APIGateway:     &apigatewayClient{b},
+		APIGatewayV2:   &apigatewayv2Client{b},
+		AutoScaling:    &autoScalingClient{b},
+	}
+}
+
+// Install makes the aws package use this backend
+func (b *Backend) Install() {
+	aws.UseClients(b.Clients(), b.fixtures.Profile, b.fixtures.Region)
+}
+
+// callerArn is the identity of the user of the fake backend
+func (b *Backend) callerArn() string {
+	return fmt.Sprintf("arn:aws:iam::%s:user/s9k", b.fixtures.AccountId)
+}
+
+func (b *Backend) arn(service, resource string) string {
+	return fmt.Sprintf("arn:aws:%s:%s:%s:%s", service, b.fixtures.Region, b.fixtures.AccountId, resource)
+}
+
+// matchesName returns true if identifier is either the full arn or the name part of the arn
+func matchesName(identifier string, arn *string) bool {
+	if arn == nil {
+		return false
+	}
+	if identifier == *arn {
+		return true
+	}
+	return identifier == (*arn)[strings.LastIndex(*arn, "/")+1:] || identifier == (*arn)[strings.LastIndex(*arn, ":")+1:]
+}
@@ -0,0 +1,260 @@
+package fake
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func TestUpdateECSImage(t *testing.T) {
+	b := NewBackend(DemoFixtures())
+	b.Install()
+
+	image := fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/dev-api:sha-27cd9e1", demoAccount, demoRegion)
+	if err := aws.UpdateECSImage(image, "api", "dev", "/dev/ecs/api/image-tag"); err != nil {
+		t.Fatalf("UpdateECSImage() failed: %v", err)
+	}
+
+	services, err := aws.DescribeClusterServices("dev")
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+	var taskDefinitionArn string
+	for _, service := range services {
+		if awssdk.ToString(service.ServiceName) == "api" {
+			taskDefinitionArn = awssdk.ToString(service.TaskDefinition)
+		}
+	}
+
+	taskDefinitions, err := aws.GetTaskDefinitions([]string{taskDefinitionArn})
+	if err != nil || len(taskDefinitions) != 1 {
+		t.Fatalf("failed to read task definition %s: %v", taskDefinitionArn, err)
+	}
+	if got := awssdk.ToString(taskDefinitions[0].ContainerDefinitions[0].Image); got != image {
+		t.Errorf("got image %s, want %s", got, image)
+	}
+
+	if got := b.fixtures.Parameters["/dev/ecs/api/image-tag"]; got != "sha-27cd9e1" {
+		t.Errorf("got image tag parameter %q, want sha-27cd9e1", got)
+	}
+}
+
+func TestDeployLambdaFunction(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	if _, err := aws.DeployLambdaFunction("email-sender", "s9k-deployments", "email-sender/v1.2.0.zip", lambdatypes.ArchitectureArm64); err != nil {
+		t.Fatalf("DeployLambdaFunction() failed: %v", err)
+	}
+	if err := aws.TagLambdaFunctionWithVersion("email-sender", "email-sender/v1.2.0.zip"); err != nil {
+		t.Fatalf("TagLambdaFunctionWithVersion() failed: %v", err)
+	}
+
+	function, err := aws.GetLambdaFunction("email-sender")
+	if err != nil {
+		t.Fatalf("failed to read the function: %v", err)
+	}
+	if got := function.Tags["LastDeployed"]; got != "email-sender/v1.2.0.zip" {
+		t.Errorf("got LastDeployed tag %q, want the deployed file", got)
+	}
+}
+
+func TestListServicesOfUnknownCluster(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	if _, err := aws.DescribeClusterServices("missing"); err == nil {
+		t.Error("listing the services of a missing cluster succeeded")
+	}
+}
+
+func TestRollbackECSService(t *testing.T) {
+	b := NewBackend(DemoFixtures())
+	b.Install()
+
+	revisions, err := aws.ListTaskDefinitionRevisions("dev-api", 2)
+	if err != nil {
+		t.Fatalf("ListTaskDefinitionRevisions() failed: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 3 || revisions[1].Revision != 2 {
+		t.Fatalf("got %d revisions, want revisions 3 and 2 newest first", len(revisions))
+	}
+
+	all, err := aws.ListTaskDefinitionRevisions("dev-api", 10)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("got %d revisions and error %v, want 3 revisions", len(all), err)
+	}
+	first := all[2]
+
+	if err := aws.RollbackECSService("api", "dev", first); err != nil {
+		t.Fatalf("RollbackECSService() failed: %v", err)
+	}
+	if err := aws.PutImageTag(aws.ServiceImage(first, "api"), "/dev/ecs/api/image-tag"); err != nil {
+		t.Fatalf("PutImageTag() failed: %v", err)
+	}
+
+	services, err := aws.DescribeClusterServices("dev")
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+	for _, service := range services {
+		if awssdk.ToString(service.ServiceName) == "api" && awssdk.ToString(service.TaskDefinition) != awssdk.ToString(first.TaskDefinitionArn) {
+			t.Errorf("got task definition %s, want %s", awssdk.ToString(service.TaskDefinition), awssdk.ToString(first.TaskDefinitionArn))
+		}
+	}
+
+	if got := b.fixtures.Parameters["/dev/ecs/api/image-tag"]; got != "sha-b81a6f0" {
+		t.Errorf("got image tag parameter %q, want the tag of revision 1", got)
+	}
+}
+
+func TestScaleECSService(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	limits, err := aws.FetchServiceScalingLimits("dev", "api")
+	if err != nil {
+		t.Fatalf("FetchServiceScalingLimits() failed: %v", err)
+	}
+	if limits == nil || limits.Min != 1 || limits.Max != 4 {
+		t.Errorf("got limits %+v, want min 1 and max 4", limits)
+	}
+
+	limits, err = aws.FetchServiceScalingLimits("dev", "web")
+	if err != nil || limits != nil {
+		t.Errorf("got limits %+v and error %v for a service without auto scaling", limits, err)
+	}
+
+	if err := aws.ScaleECSService("dev", "web", 3); err != nil {
+		t.Fatalf("ScaleECSService() failed: %v", err)
+	}
+
+	services, err := aws.DescribeClusterServices("dev")
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+	for _, service := range services {
+		if awssdk.ToString(service.ServiceName) == "web" && service.DesiredCount != 3 {
+			t.Errorf("got desired count %d, want 3", service.DesiredCount)
+		}
+	}
+}
+
+func TestSetServiceMinCapacity(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	if err := aws.SetServiceMinCapacity("dev", "api", 0); err != nil {
+		t.Fatalf("SetServiceMinCapacity() failed: %v", err)
+	}
+
+	limits, err := aws.FetchServiceScalingLimits("dev", "api")
+	if err != nil {
+		t.Fatalf("FetchServiceScalingLimits() failed: %v", err)
+	}
+	if limits == nil || limits.Min != 0 || limits.Max != 4 {
+		t.Errorf("got limits %+v, want min 0 and max 4", limits)
+	}
+
+	if err := aws.SetServiceMinCapacity("dev", "web", 1); err == nil {
+		t.Error("setting the minimum capacity of a service without auto scaling succeeded")
+	}
+}
+
+func TestInvokeLambdaFunction(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	result, err := aws.InvokeLambdaFunction("orders-handler", []byte(`{"orderId": 42}`), false)
+	if err != nil {
+		t.Fatalf("InvokeLambdaFunction() failed: %v", err)
+	}
+	if result.StatusCode != 200 || result.FunctionError != "" || !strings.Contains(result.Log, "START RequestId") {
+		t.Errorf("got result %+v, want a successful invocation with the log", result)
+	}
+
+	result, err = aws.InvokeLambdaFunction("orders-handler", []byte(`{"error": "boom"}`), false)
+	if err != nil || result.FunctionError != "Unhandled" {
+		t.Errorf("got result %+v and error %v, want a function error", result, err)
+	}
+
+	result, err = aws.InvokeLambdaFunction("orders-handler", []byte(`{}`), true)
+	if err != nil || result.StatusCode != 202 || result.Log != "" {
+		t.Errorf("got result %+v and error %v, want a queued event", result, err)
+	}
+}
+
+func TestValidateFilterPattern(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	if err := aws.ValidateFilterPattern("/aws/lambda/email-sender", `{ $.level = "ERROR" }`); err != nil {
+		t.Errorf("ValidateFilterPattern() of a valid pattern failed: %v", err)
+	}
+
+	err := aws.ValidateFilterPattern("/aws/lambda/email-sender", `{ $.level = "ERROR" `)
+	if err == nil {
+		t.Fatal("ValidateFilterPattern() of an invalid pattern succeeded")
+	}
+	if got := aws.ErrorMessage(err); !strings.HasPrefix(got, "Invalid filter pattern") {
+		t.Errorf("got message %q, want the message from CloudWatch", got)
+	}
+}
+
+func TestGetLambdaConfigurationWithEnvironmentError(t *testing.T) {
+	fixtures := DemoFixtures()
+	for i := range fixtures.Functions {
+		if awssdk.ToString(fixtures.Functions[i].FunctionName) == "email-sender" {
+			fixtures.Functions[i].Environment = &lambdatypes.EnvironmentResponse{Error: &lambdatypes.EnvironmentError{
+				ErrorCode: awssdk.String("KMSAccessDeniedException"),
+				Message:   awssdk.String("Lambda was unable to decrypt the environment variables"),
+			}}
+		}
+	}
+	NewBackend(fixtures).Install()
+
+	if _, err := aws.GetLambdaConfiguration("email-sender"); err == nil || !strings.Contains(err.Error(), "KMSAccessDeniedException") {
+		t.Errorf("got error %v, want the environment error", err)
+	}
+
+	configuration, err := aws.GetLambdaConfiguration("orders-handler")
+	if err != nil || configuration.Environment["TABLE_NAME"] != "orders-handler" {
+		t.Errorf("got configuration %+v and error %v, want the environment variables", configuration, err)
+	}
+}
+
+func TestUpdateLambdaConfiguration(t *testing.T) {
+	NewBackend(DemoFixtures()).Install()
+
+	configuration, err := aws.GetLambdaConfiguration("email-sender")
+	if err != nil {
+		t.Fatalf("GetLambdaConfiguration() failed: %v", err)
+	}
+	variables := configuration.Environment
+
+	// without environment variables in the update the variables are kept
+	configuration.MemorySize = 1024
+	configuration.Environment = nil
+	if err := aws.UpdateLambdaConfiguration("email-sender", *configuration); err != nil {
+		t.Fatalf("UpdateLambdaConfiguration() failed: %v", err)
+	}
+
+	updated, err := aws.GetLambdaConfiguration("email-sender")
+	if err != nil {
+		t.Fatalf("GetLambdaConfiguration() failed: %v", err)
+	}
+	if updated.MemorySize != 1024 || !maps.Equal(updated.Environment, variables) {
+		t.Errorf("got memory size %d and variables %v, want 1024 and %v", updated.MemorySize, updated.Environment, variables)
+	}
+
+	// with environment variables in the update all variables are replaced
+	configuration.Environment = map[string]string{"LOG_LEVEL": "debug"}
+	if err := aws.UpdateLambdaConfiguration("email-sender", *configuration); err != nil {
+		t.Fatalf("UpdateLambdaConfiguration() failed: %v", err)
+	}
+
+	updated, err = aws.GetLambdaConfiguration("email-sender")
+	if err != nil || !maps.Equal(updated.Environment, configuration.Environment) {
+		t.Errorf("got variables %v and error %v, want %v", updated.Environment, err, configuration.Environment)
+	}
+}
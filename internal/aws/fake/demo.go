@@ -0,0 +1,332 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+const (
+	demoAccount = "123456789012"
+	demoRegion  = "eu-north-1"
+)
+
+var (
+	demoClusters  = []string{"dev", "test", "prod"}
+	demoServices  = []string{"api", "web", "worker"}
+	demoFunctions = []string{"orders-handler", "email-sender", "report-generator"}
+	demoShas      = []string{"4f1c2ab", "9e0d7c3", "b81a6f0", "27cd9e1", "e3f5a48"}
+	demoUsers     = []string{"alice", "bob", "github-actions"}
+)
+
+// DemoFixtures returns fixtures describing a small account with three ECS clusters, a few lambda functions and
+// apis, suitable for demos and for running the application without an AWS account
+func DemoFixtures() Fixtures {
+	now := time.Now()
+
+	fixtures := Fixtures{
+		AccountId:              demoAccount,
+		Region:                 demoRegion,
+		Profile:                "demo",
+		Bucket:                 "s9k-deployments",
+		FunctionTags:           make(map[string]map[string]string),
+		FunctionVersions:       make(map[string][]lambdatypes.FunctionConfiguration),
+		FunctionAliases:        make(map[string][]lambdatypes.AliasConfiguration),
+		ReservedConcurrency:    make(map[string]int32),
+		ProvisionedConcurrency: make(map[string][]lambdatypes.ProvisionedConcurrencyConfigListItem),
+		Parameters:             make(map[string]string),
+		ApiMappings:            make(map[string][]apigatewayv2types.ApiMapping),
+		ParameterHistory:       make(map[string][]ssmtypes.ParameterHistory),
+		LogMessages: []string{
+			`{"timestamp":"{{timestamp}}","level":"INFO","message":"Handled request","path":"/orders","status":200,"traceId":"1-5f8a-demo"}`,
+			`{"timestamp":"{{timestamp}}","level":"DEBUG","message":"Cache hit","key":"customer:42","traceId":"1-5f8b-demo"}`,
+			`{{timestamp}} INFO Started background job`,
+			`{"timestamp":"{{timestamp}}","level":"WARN","message":"Slow query","durationMs":1250,"traceId":"1-5f8c-demo"}`,
+			`{"timestamp":"{{timestamp}}","level":"ERROR","message":"Failed to send email","error":"connection reset","traceId":"1-5f8d-demo"}`,
+		},
+	}
+
+	for c, clusterName := range demoClusters {
+		clusterArn := fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", demoRegion, demoAccount, clusterName)
+
+		fixtures.Clusters = append(fixtures.Clusters, ecstypes.Cluster{
+			ClusterArn:          awssdk.String(clusterArn),
+			ClusterName:         awssdk.String(clusterName),
+			Status:              awssdk.String("ACTIVE"),
+			ActiveServicesCount: int32(len(demoServices)),
+			RunningTasksCount:   int32(len(demoServices) * (c + 1)),
+			Statistics: []ecstypes.KeyValuePair{
+				{Name: awssdk.String("runningFargateTasksCount"), Value: awssdk.String(fmt.Sprint(len(demoServices) * (c + 1)))},
+				{Name: awssdk.String("runningEC2TasksCount"), Value: awssdk.String("0")},
+			},
+		})
+
+		for s, serviceName := range demoServices {
+			family := fmt.Sprintf("%s-%s", clusterName, serviceName)
+			repository := family
+			sha := demoShas[(c+s)%len(demoShas)]
+			image := fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:sha-%s", demoAccount, demoRegion, repository, sha)
+			logGroup := fmt.Sprintf("/ecs/%s", family)
+			desired := int32(c + 1)
+			deployedAt := now.Add(-time.Duration(s+1) * time.Hour)
+
+			// a revision was registered a day before the next one, the last one is deployed
+			for revision := int32(1); revision <= 3; revision++ {
+				registeredAt := deployedAt.Add(-time.Duration(3-revision) * 24 * time.Hour)
+				revisionImage := fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:sha-%s", demoAccount, demoRegion, repository, demoShas[(c+s+3-int(revision))%len(demoShas)])
+
+				fixtures.TaskDefinitions = append(fixtures.TaskDefinitions, ecstypes.TaskDefinition{
+					TaskDefinitionArn: awssdk.String(fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s:%d", demoRegion, demoAccount, family, revision)),
+					Family:            awssdk.String(family),
+					Revision:          revision,
+					Status:            ecstypes.TaskDefinitionStatusActive,
+					Cpu:               awssdk.String("256"),
+					Memory:            awssdk.String("512"),
+					RegisteredAt:      &registeredAt,
+					ContainerDefinitions: []ecstypes.ContainerDefinition{
+						{
+							Name:  awssdk.String(serviceName),
+							Image: awssdk.String(revisionImage),
+							LogConfiguration: &ecstypes.LogConfiguration{
+								LogDriver: ecstypes.LogDriverAwslogs,
+								Options: map[string]string{
+									"awslogs-group":         logGroup,
+									"awslogs-stream-prefix": serviceName,
+									"awslogs-region":        demoRegion,
+								},
+							},
+						},
+					},
+				})
+			}
+
+			taskDefinitionArn := fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s:3", demoRegion, demoAccount, family)
+
+			// the worker has no load balancer
+			var loadBalancers []ecstypes.LoadBalancer
+			if serviceName != "worker" {
+				loadBalancers = []ecstypes.LoadBalancer{{
+					TargetGroupArn: awssdk.String(fmt.Sprintf("arn:aws:elasticloadbalancing:%s:%s:targetgroup/%s/%016x", demoRegion, demoAccount, family, seedOf(family))),
+					ContainerName:  awssdk.String(serviceName),
+					ContainerPort:  awssdk.Int32(8080),
+				}}
+			}
+
+			fixtures.Services = append(fixtures.Services, ecstypes.Service{
+				ServiceArn:     awssdk.String(fmt.Sprintf("arn:aws:ecs:%s:%s:service/%s/%s", demoRegion, demoAccount, clusterName, serviceName)),
+				ServiceName:    awssdk.String(serviceName),
+				ClusterArn:     awssdk.String(clusterArn),
+				TaskDefinition: awssdk.String(taskDefinitionArn),
+				Status:         awssdk.String("ACTIVE"),
+				LaunchType:     ecstypes.LaunchTypeFargate,
+				LoadBalancers:  loadBalancers,
+				DesiredCount:   desired,
+				RunningCount:   desired,
+				DeploymentConfiguration: &ecstypes.DeploymentConfiguration{
+					DeploymentCircuitBreaker: &ecstypes.DeploymentCircuitBreaker{Enable: true, Rollback: true},
+					MaximumPercent:           awssdk.Int32(200),
+					MinimumHealthyPercent:    awssdk.Int32(100),
+				},
+				Events: []ecstypes.ServiceEvent{
+					{
+						Id:        awssdk.String(fmt.Sprintf("%s-%s-steady", clusterName, serviceName)),
+						CreatedAt: &deployedAt,
+						Message:   awssdk.String(fmt.Sprintf("(service %s) has reached a steady state.", serviceName)),
+					},
+				},
+				Deployments: []ecstypes.Deployment{
+					{
+						Id:             awssdk.String(fmt.Sprintf("ecs-svc/%s-%s", clusterName, serviceName)),
+						Status:         awssdk.String("PRIMARY"),
+						TaskDefinition: awssdk.String(taskDefinitionArn),
+						DesiredCount:   desired,
+						RunningCount:   desired,
+						RolloutState:   ecstypes.DeploymentRolloutStateCompleted,
+						CreatedAt:      &deployedAt,
+						UpdatedAt:      &deployedAt,
+					},
+				},
+			})
+
+			for t := int32(0); t < desired; t++ {
+				taskId := fmt.Sprintf("%s%s%02d%s", clusterName, serviceName, t, sha)
+				fixtures.Tasks = append(fixtures.Tasks, ecstypes.Task{
+					TaskArn:           awssdk.String(fmt.Sprintf("arn:aws:ecs:%s:%s:task/%s/%s", demoRegion, demoAccount, clusterName, taskId)),
+					ClusterArn:        awssdk.String(clusterArn),
+					TaskDefinitionArn: awssdk.String(taskDefinitionArn),
+					Group:             awssdk.String("service:" + serviceName),
+					LastStatus:        awssdk.String("RUNNING"),
+					Containers: []ecstypes.Container{
+						{
+							Name:         awssdk.String(serviceName),
+							Image:        awssdk.String(image),
+							LastStatus:   awssdk.String("RUNNING"),
+							HealthStatus: ecstypes.HealthStatusHealthy,
+							Memory:       awssdk.String("512"),
+							Cpu:          awssdk.String("256"),
+						},
+					},
+				})
+			}
+
+			for i, imageSha := range demoShas {
+				pushedAt := now.Add(-time.Duration(i+1) * 26 * time.Hour)
+				fixtures.Images = append(fixtures.Images, ecrtypes.ImageDetail{
+					RegistryId:     awssdk.String(demoAccount),
+					RepositoryName: awssdk.String(repository),
+					ImageDigest:    awssdk.String(fmt.Sprintf("sha256:%s%s", imageSha, imageSha)),
+					ImageTags:      []string{"sha-" + imageSha},
+					ImagePushedAt:  &pushedAt,
+				})
+			}
+
+			// the image tag parameter was set right after each task definition revision was deployed
+			parameter := fmt.Sprintf("/%s/ecs/%s/image-tag", clusterName, serviceName)
+			fixtures.Parameters[parameter] = "sha-" + sha
+			for revision := int32(1); revision <= 3; revision++ {
+				updatedAt := deployedAt.Add(-time.Duration(3-revision)*24*time.Hour + 20*time.Second)
+				fixtures.ParameterHistory[parameter] = append(fixtures.ParameterHistory[parameter], ssmtypes.ParameterHistory{
+					Name:             awssdk.String(parameter),
+					Value:            awssdk.String("sha-" + demoShas[(c+s+3-int(revision))%len(demoShas)]),
+					Version:          int64(revision),
+					LastModifiedDate: &updatedAt,
+					LastModifiedUser: awssdk.String(fmt.Sprintf("arn:aws:iam::%s:user/%s", demoAccount, demoUsers[(c+s+int(revision))%len(demoUsers)])),
+				})
+			}
+
+			// the api services scale with Application Auto Scaling
+			if serviceName == "api" {
+				fixtures.ScalableTargets = append(fixtures.ScalableTargets, autoscalingtypes.ScalableTarget{
+					ServiceNamespace:  autoscalingtypes.ServiceNamespaceEcs,
+					ScalableDimension: autoscalingtypes.ScalableDimensionECSServiceDesiredCount,
+					ResourceId:        awssdk.String(fmt.Sprintf("service/%s/%s", clusterName, serviceName)),
+					MinCapacity:       awssdk.Int32(desired),
+					MaxCapacity:       awssdk.Int32(desired + 3),
+				})
+			}
+		}
+	}
+
+	for i, functionName := range demoFunctions {
+		modified := now.Add(-time.Duration(i+2) * time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700")
+
+		fixtures.Functions = append(fixtures.Functions, lambdatypes.FunctionConfiguration{
+			FunctionName:     awssdk.String(functionName),
+			FunctionArn:      awssdk.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", demoRegion, demoAccount, functionName)),
+			Description:      awssdk.String(fmt.Sprintf("Demo function %s", functionName)),
+			Runtime:          lambdatypes.RuntimeProvidedal2023,
+			PackageType:      lambdatypes.PackageTypeZip,
+			Handler:          awssdk.String("bootstrap"),
+			CodeSize:         int64(4200000 + i*350000),
+			MemorySize:       awssdk.Int32(int32(128 << i)),
+			Timeout:          awssdk.Int32(int32(10 * (i + 1))),
+			Architectures:    []lambdatypes.Architecture{lambdatypes.ArchitectureArm64},
+			LastModified:     awssdk.String(modified),
+			LastUpdateStatus: lambdatypes.LastUpdateStatusSuccessful,
+			State:            lambdatypes.StateActive,
+			EphemeralStorage: &lambdatypes.EphemeralStorage{Size: awssdk.Int32(512)},
+			Environment: &lambdatypes.EnvironmentResponse{Variables: map[string]string{
+				"LOG_LEVEL":  "info",
+				"TABLE_NAME": functionName,
+			}},
+			LoggingConfig: &lambdatypes.LoggingConfig{
+				LogGroup:  awssdk.String("/aws/lambda/" + functionName),
+				LogFormat: lambdatypes.LogFormatJson,
+			},
+		})
+
+		fixtures.FunctionTags[functionName] = map[string]string{
+			"LastDeployed":   fmt.Sprintf("v1.%d.0.zip", i),
+			"LastDeployedBy": fmt.Sprintf("arn:aws:iam::%s:user/%s", demoAccount, demoUsers[i%len(demoUsers)]),
+			"LastDeployedAt": now.Add(-time.Duration(i+2) * time.Hour).UTC().Format(time.RFC3339),
+		}
+
+		// three published versions, the newest is the current code of the function
+		for v := 1; v <= 3; v++ {
+			version := fixtures.Functions[len(fixtures.Functions)-1]
+			version.Version = awssdk.String(strconv.Itoa(v))
+			version.FunctionArn = awssdk.String(fmt.Sprintf("%s:%d", *version.FunctionArn, v))
+			version.Description = awssdk.String(fmt.Sprintf("Release %d", v))
+			if v < 3 {
+				version.LastModified = awssdk.String(now.Add(-time.Duration(4-v) * 30 * time.Hour).UTC().Format("2006-01-02T15:04:05.000-0700"))
+			}
+			fixtures.FunctionVersions[functionName] = append(fixtures.FunctionVersions[functionName], version)
+		}
+
+		// the first function is halfway through shifting traffic to its newest version
+		live := lambdatypes.AliasConfiguration{
+			Name:            awssdk.String("live"),
+			AliasArn:        awssdk.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s:live", demoRegion, demoAccount, functionName)),
+			FunctionVersion: awssdk.String("3"),
+		}
+		if i == 0 {
+			live.FunctionVersion = awssdk.String("2")
+			live.RoutingConfig = &lambdatypes.AliasRoutingConfiguration{AdditionalVersionWeights: map[string]float64{"3": 0.1}}
+		}
+		fixtures.FunctionAliases[functionName] = []lambdatypes.AliasConfiguration{live}
+
+		// the first function has concurrency reserved and provisioned on its alias
+		if i == 0 {
+			fixtures.ReservedConcurrency[functionName] = 100
+			fixtures.ProvisionedConcurrency[functionName] = []lambdatypes.ProvisionedConcurrencyConfigListItem{
+				{
+					FunctionArn:                              live.AliasArn,
+					RequestedProvisionedConcurrentExecutions: awssdk.Int32(5),
+					AllocatedProvisionedConcurrentExecutions: awssdk.Int32(5),
+					AvailableProvisionedConcurrentExecutions: awssdk.Int32(5),
+					Status:                                   lambdatypes.ProvisionedConcurrencyStatusEnumReady,
+				},
+			}
+		}
+
+		for v := 0; v < 3; v++ {
+			uploadedAt := now.Add(-time.Duration(v+1) * 30 * time.Hour)
+			fixtures.Objects = append(fixtures.Objects, s3types.Object{
+				Key:          awssdk.String(fmt.Sprintf("%s/v1.%d.0.zip", functionName, v)),
+				LastModified: &uploadedAt,
+				Size:         awssdk.Int64(4200000),
+			})
+		}
+	}
+
+	createdAt := now.Add(-90 * 24 * time.Hour)
+
+	fixtures.HttpApis = []apigatewayv2types.Api{
+		{
+			ApiId:        awssdk.String("a1b2c3d4e5"),
+			Name:         awssdk.String("orders-api"),
+			Description:  awssdk.String("Public orders api"),
+			ProtocolType: apigatewayv2types.ProtocolTypeHttp,
+			CreatedDate:  &createdAt,
+		},
+	}
+
+	fixtures.RestApis = []apigatewaytypes.RestApi{
+		{
+			Id:          awssdk.String("f6g7h8i9j0"),
+			Name:        awssdk.String("legacy-api"),
+			Description: awssdk.String("Legacy rest api"),
+			CreatedDate: &createdAt,
+		},
+	}
+
+	fixtures.DomainNames = []apigatewayv2types.DomainName{
+		{DomainName: awssdk.String("api.example.com")},
+	}
+
+	fixtures.ApiMappings["api.example.com"] = []apigatewayv2types.ApiMapping{
+		{ApiId: awssdk.String("a1b2c3d4e5"), Stage: awssdk.String("$default")},
+	}
+
+	return fixtures
+}
@@ -0,0 +1,397 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+type ecsClient struct {
+	b *Backend
+}
+
+func (c *ecsClient) ListClusters(_ context.Context, _ *ecs.ListClustersInput, _ ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	arns := make([]string, 0, len(c.b.fixtures.Clusters))
+	for _, v := range c.b.fixtures.Clusters {
+		arns = append(arns, *v.ClusterArn)
+	}
+
+	return &ecs.ListClustersOutput{ClusterArns: arns}, nil
+}
+
+func (c *ecsClient) DescribeClusters(_ context.Context, params *ecs.DescribeClustersInput, _ ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	clusters := make([]ecstypes.Cluster, 0)
+	for _, identifier := range params.Clusters {
+		for _, v := range c.b.fixtures.Clusters {
+			if matchesName(identifier, v.ClusterArn) {
+				clusters = append(clusters, v)
+			}
+		}
+	}
+
+	return &ecs.DescribeClustersOutput{Clusters: clusters}, nil
+}
+
+func (c *ecsClient) ListServices(_ context.Context, params *ecs.ListServicesInput, _ ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	cluster := awssdk.ToString(params.Cluster)
+	if !slices.ContainsFunc(c.b.fixtures.Clusters, func(v ecstypes.Cluster) bool { return matchesName(cluster, v.ClusterArn) }) {
+		return nil, &ecstypes.ClusterNotFoundException{Message: awssdk.String("Cluster not found.")}
+	}
+
+	arns := make([]string, 0)
+	for _, v := range c.b.fixtures.Services {
+		if matchesName(awssdk.ToString(params.Cluster), v.ClusterArn) {
+			arns = append(arns, *v.ServiceArn)
+		}
+	}
+
+	return &ecs.ListServicesOutput{ServiceArns: arns}, nil
+}
+
+func (c *ecsClient) DescribeServices(_ context.Context, params *ecs.DescribeServicesInput, _ ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	services := make([]ecstypes.Service, 0)
+	for _, identifier := range params.Services {
+		if i := c.b.findService(awssdk.ToString(params.Cluster), identifier); i >= 0 {
+			advanceRollout(&c.b.fixtures.Services[i], time.Now())
+
+			service := c.b.fixtures.Services[i]
+			service.Tags = c.b.resourceTags(*service.ServiceArn, service.Tags)
+			services = append(services, service)
+		}
+	}
+
+	return &ecs.DescribeServicesOutput{Services: services}, nil
+}
+
+func (c *ecsClient) UpdateService(_ context.Context, params *ecs.UpdateServiceInput, _ ...func(*ecs.Options)) (*ecs.UpdateServiceOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findService(awssdk.ToString(params.Cluster), awssdk.ToString(params.Service))
+	if i < 0 {
+		return nil, fmt.Errorf("service %s: %w", awssdk.ToString(params.Service), errNotFound)
+	}
+
+	service := &c.b.fixtures.Services[i]
+
+	if params.DesiredCount != nil {
+		service.DesiredCount = *params.DesiredCount
+		service.RunningCount = *params.DesiredCount
+		service.PendingCount = 0
+
+		for d := range service.Deployments {
+			if awssdk.ToString(service.Deployments[d].Status) == "PRIMARY" {
+				service.Deployments[d].DesiredCount = *params.DesiredCount
+				service.Deployments[d].RunningCount = *params.DesiredCount
+			}
+		}
+	}
+
+	if params.TaskDefinition != nil || params.ForceNewDeployment {
+		if params.TaskDefinition != nil {
+			service.TaskDefinition = params.TaskDefinition
+		}
+
+		// The new deployment starts without tasks and is rolled out by advanceRollouts when the service is
+		// described, the previous deployments are drained as the new tasks start
+		now := time.Now()
+		for d := range service.Deployments {
+			service.Deployments[d].Status = awssdk.String("ACTIVE")
+		}
+		service.Deployments = append([]ecstypes.Deployment{
+			{
+				Id:                 awssdk.String(fmt.Sprintf("ecs-svc/%d", now.UnixNano())),
+				Status:             awssdk.String("PRIMARY"),
+				TaskDefinition:     service.TaskDefinition,
+				DesiredCount:       service.DesiredCount,
+				PendingCount:       service.DesiredCount,
+				RolloutState:       ecstypes.DeploymentRolloutStateInProgress,
+				RolloutStateReason: awssdk.String("ECS deployment in progress."),
+				CreatedAt:          &now,
+				UpdatedAt:          &now,
+			},
+		}, service.Deployments...)
+		addServiceEvent(service, now, fmt.Sprintf("(service %s) has started a deployment of %s.", awssdk.ToString(service.ServiceName), awssdk.ToString(service.TaskDefinition)))
+	}
+
+	return &ecs.UpdateServiceOutput{Service: service}, nil
+}
+
+func (c *ecsClient) TagResource(_ context.Context, params *ecs.TagResourceInput, _ ...func(*ecs.Options)) (*ecs.TagResourceOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	arn := awssdk.ToString(params.ResourceArn)
+	if c.b.tags[arn] == nil {
+		c.b.tags[arn] = make(map[string]string)
+	}
+
+	for _, v := range params.Tags {
+		c.b.tags[arn][awssdk.ToString(v.Key)] = awssdk.ToString(v.Value)
+	}
+
+	return &ecs.TagResourceOutput{}, nil
+}
+
+func (c *ecsClient) UntagResource(_ context.Context, params *ecs.UntagResourceInput, _ ...func(*ecs.Options)) (*ecs.UntagResourceOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	for _, key := range params.TagKeys {
+		delete(c.b.tags[awssdk.ToString(params.ResourceArn)], key)
+	}
+
+	return &ecs.UntagResourceOutput{}, nil
+}
+
+func (c *ecsClient) DescribeTaskDefinition(_ context.Context, params *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findTaskDefinition(awssdk.ToString(params.TaskDefinition))
+	if i < 0 {
+		return nil, fmt.Errorf("task definition %s: %w", awssdk.ToString(params.TaskDefinition), errNotFound)
+	}
+
+	taskDefinition := c.b.fixtures.TaskDefinitions[i]
+
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: &taskDefinition}, nil
+}
+
+func (c *ecsClient) RegisterTaskDefinition(_ context.Context, params *ecs.RegisterTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.RegisterTaskDefinitionOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	family := awssdk.ToString(params.Family)
+
+	revision := int32(1)
+	for _, v := range c.b.fixtures.TaskDefinitions {
+		if awssdk.ToString(v.Family) == family && v.Revision >= revision {
+			revision = v.Revision + 1
+		}
+	}
+
+	now := time.Now()
+	taskDefinition := ecstypes.TaskDefinition{
+		TaskDefinitionArn:       awssdk.String(c.b.arn("ecs", fmt.Sprintf("task-definition/%s:%d", family, revision))),
+		Family:                  params.Family,
+		Revision:                revision,
+		ContainerDefinitions:    params.ContainerDefinitions,
+		Cpu:                     params.Cpu,
+		Memory:                  params.Memory,
+		NetworkMode:             params.NetworkMode,
+		RequiresCompatibilities: params.RequiresCompatibilities,
+		ExecutionRoleArn:        params.ExecutionRoleArn,
+		TaskRoleArn:             params.TaskRoleArn,
+		Status:                  ecstypes.TaskDefinitionStatusActive,
+		RegisteredAt:            &now,
+	}
+
+	c.b.fixtures.TaskDefinitions = append(c.b.fixtures.TaskDefinitions, taskDefinition)
+
+	return &ecs.RegisterTaskDefinitionOutput{TaskDefinition: &taskDefinition}, nil
+}
+
+func (c *ecsClient) ListTaskDefinitions(_ context.Context, params *ecs.ListTaskDefinitionsInput, _ ...func(*ecs.Options)) (*ecs.ListTaskDefinitionsOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	taskDefinitions := make([]ecstypes.TaskDefinition, 0)
+	for _, v := range c.b.fixtures.TaskDefinitions {
+		if params.FamilyPrefix != nil && awssdk.ToString(v.Family) != *params.FamilyPrefix {
+			continue
+		}
+		if params.Status != "" && v.Status != params.Status {
+			continue
+		}
+		taskDefinitions = append(taskDefinitions, v)
+	}
+
+	sort.SliceStable(taskDefinitions, func(i, j int) bool {
+		if params.Sort == ecstypes.SortOrderDesc {
+			i, j = j, i
+		}
+		if *taskDefinitions[i].Family != *taskDefinitions[j].Family {
+			return *taskDefinitions[i].Family < *taskDefinitions[j].Family
+		}
+		return taskDefinitions[i].Revision < taskDefinitions[j].Revision
+	})
+
+	if params.MaxResults != nil && len(taskDefinitions) > int(*params.MaxResults) {
+		taskDefinitions = taskDefinitions[:*params.MaxResults]
+	}
+
+	arns := make([]string, 0, len(taskDefinitions))
+	for _, v := range taskDefinitions {
+		arns = append(arns, *v.TaskDefinitionArn)
+	}
+
+	return &ecs.ListTaskDefinitionsOutput{TaskDefinitionArns: arns}, nil
+}
+
+func (c *ecsClient) StopTask(_ context.Context, params *ecs.StopTaskInput, _ ...func(*ecs.Options)) (*ecs.StopTaskOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	for i, v := range c.b.fixtures.Tasks {
+		if matchesName(awssdk.ToString(params.Task), v.TaskArn) {
+			c.b.fixtures.Tasks = append(c.b.fixtures.Tasks[:i], c.b.fixtures.Tasks[i+1:]...)
+			return &ecs.StopTaskOutput{Task: &v}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("task %s: %w", awssdk.ToString(params.Task), errNotFound)
+}
+
+func (c *ecsClient) ListTasks(_ context.Context, params *ecs.ListTasksInput, _ ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	arns := make([]string, 0)
+	for _, v := range c.b.fixtures.Tasks {
+		if !matchesName(awssdk.ToString(params.Cluster), v.ClusterArn) {
+			continue
+		}
+		if params.ServiceName != nil && awssdk.ToString(v.Group) != "service:"+*params.ServiceName {
+			continue
+		}
+		arns = append(arns, *v.TaskArn)
+	}
+
+	return &ecs.ListTasksOutput{TaskArns: arns}, nil
+}
+
+func (c *ecsClient) DescribeTasks(_ context.Context, params *ecs.DescribeTasksInput, _ ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	tasks := make([]ecstypes.Task, 0)
+	for _, identifier := range params.Tasks {
+		for _, v := range c.b.fixtures.Tasks {
+			if matchesName(identifier, v.TaskArn) {
+				tasks = append(tasks, v)
+			}
+		}
+	}
+
+	return &ecs.DescribeTasksOutput{Tasks: tasks}, nil
+}
+
+func (c *ecsClient) ListContainerInstances(_ context.Context, _ *ecs.ListContainerInstancesInput, _ ...func(*ecs.Options)) (*ecs.ListContainerInstancesOutput, error) {
+	return &ecs.ListContainerInstancesOutput{}, nil
+}
+
+func (c *ecsClient) DescribeContainerInstances(_ context.Context, _ *ecs.DescribeContainerInstancesInput, _ ...func(*ecs.Options)) (*ecs.DescribeContainerInstancesOutput, error) {
+	return &ecs.DescribeContainerInstancesOutput{}, nil
+}
+
+func (c *ecsClient) ExecuteCommand(_ context.Context, _ *ecs.ExecuteCommandInput, _ ...func(*ecs.Options)) (*ecs.ExecuteCommandOutput, error) {
+	return nil, errors.New("execute command is not supported by the fake backend")
+}
+
+// rolloutTaskInterval is the time it takes the fake to start one task of a new deployment
+const rolloutTaskInterval = 3 * time.Second
+
+// advanceRollout moves an in progress deployment forward based on the time since it was created. One task is
+// started every rolloutTaskInterval and the deployment is completed one interval after all tasks are running
+func advanceRollout(service *ecstypes.Service, now time.Time) {
+	if len(service.Deployments) == 0 || service.Deployments[0].RolloutState != ecstypes.DeploymentRolloutStateInProgress {
+		return
+	}
+
+	primary := &service.Deployments[0]
+	elapsed := now.Sub(*primary.CreatedAt)
+	running := min(int32(elapsed/rolloutTaskInterval), primary.DesiredCount)
+
+	if running > primary.RunningCount {
+		addServiceEvent(service, now, fmt.Sprintf("(service %s) has started %d tasks.", awssdk.ToString(service.ServiceName), running-primary.RunningCount))
+
+		primary.RunningCount = running
+		primary.PendingCount = min(primary.DesiredCount-running, 1)
+		primary.UpdatedAt = &now
+
+		// old tasks are stopped as new tasks start
+		for d := 1; d < len(service.Deployments); d++ {
+			service.Deployments[d].RunningCount = max(service.Deployments[d].DesiredCount-running, 0)
+		}
+	}
+
+	if running == primary.DesiredCount && elapsed >= time.Duration(primary.DesiredCount+1)*rolloutTaskInterval {
+		primary.RolloutState = ecstypes.DeploymentRolloutStateCompleted
+		primary.RolloutStateReason = awssdk.String("ECS deployment completed.")
+		primary.PendingCount = 0
+		primary.UpdatedAt = &now
+		service.Deployments = service.Deployments[:1]
+
+		addServiceEvent(service, now, fmt.Sprintf("(service %s) has reached a steady state.", awssdk.ToString(service.ServiceName)))
+	}
+}
+
+// addServiceEvent adds an event to the service, newest first as returned by ECS
+func addServiceEvent(service *ecstypes.Service, now time.Time, message string) {
+	event := ecstypes.ServiceEvent{
+		Id:        awssdk.String(fmt.Sprintf("%d", now.UnixNano())),
+		CreatedAt: &now,
+		Message:   awssdk.String(message),
+	}
+
+	service.Events = append([]ecstypes.ServiceEvent{event}, service.Events...)
+	if len(service.Events) > 100 {
+		service.Events = service.Events[:100]
+	}
+}
+
+// resourceTags returns the fixture tags of a resource merged with the tags added with TagResource
+func (b *Backend) resourceTags(arn string, fixtureTags []ecstypes.Tag) []ecstypes.Tag {
+	tags := make([]ecstypes.Tag, 0, len(fixtureTags)+len(b.tags[arn]))
+	tags = append(tags, fixtureTags...)
+	for k, v := range b.tags[arn] {
+		tags = append(tags, ecstypes.Tag{Key: awssdk.String(k), Value: awssdk.String(v)})
+	}
+	return tags
+}
+
+// findService returns the index of the service in the given cluster, or -1 if not found
+func (b *Backend) findService(cluster, service string) int {
+	for i, v := range b.fixtures.Services {
+		if matchesName(cluster, v.ClusterArn) && (matchesName(service, v.ServiceArn) || service == awssdk.ToString(v.ServiceName)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findTaskDefinition returns the index of the task definition identified by arn, family:revision or family (latest
+// revision), or -1 if not found
+func (b *Backend) findTaskDefinition(identifier string) int {
+	found := -1
+	for i, v := range b.fixtures.TaskDefinitions {
+		if matchesName(identifier, v.TaskDefinitionArn) || strings.HasSuffix(awssdk.ToString(v.TaskDefinitionArn), "/"+identifier) {
+			return i
+		}
+		if awssdk.ToString(v.Family) == identifier && (found < 0 || v.Revision > b.fixtures.TaskDefinitions[found].Revision) {
+			found = i
+		}
+	}
+	return found
+}
@@ -0,0 +1,556 @@
+package fake
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+type lambdaClient struct {
+	b *Backend
+}
+
+func (c *lambdaClient) ListFunctions(_ context.Context, _ *lambda.ListFunctionsInput, _ ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	c.b.completeFunctionUpdates(time.Now())
+
+	functions := make([]lambdatypes.FunctionConfiguration, len(c.b.fixtures.Functions))
+	copy(functions, c.b.fixtures.Functions)
+
+	return &lambda.ListFunctionsOutput{Functions: functions}, nil
+}
+
+func (c *lambdaClient) GetFunction(_ context.Context, params *lambda.GetFunctionInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	c.b.completeFunctionUpdates(time.Now())
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := c.b.fixtures.Functions[i]
+
+	output := &lambda.GetFunctionOutput{
+		Configuration: &function,
+		Tags:          c.b.fixtures.FunctionTags[*function.FunctionName],
+	}
+	if reserved, found := c.b.fixtures.ReservedConcurrency[*function.FunctionName]; found {
+		output.Concurrency = &lambdatypes.Concurrency{ReservedConcurrentExecutions: awssdk.Int32(reserved)}
+	}
+
+	return output, nil
+}
+
+func (c *lambdaClient) GetFunctionConfiguration(_ context.Context, params *lambda.GetFunctionConfigurationInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionConfigurationOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	c.b.completeFunctionUpdates(time.Now())
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := c.b.fixtures.Functions[i]
+
+	return &lambda.GetFunctionConfigurationOutput{
+		FunctionName:     function.FunctionName,
+		FunctionArn:      function.FunctionArn,
+		Description:      function.Description,
+		MemorySize:       function.MemorySize,
+		Timeout:          function.Timeout,
+		Runtime:          function.Runtime,
+		Architectures:    function.Architectures,
+		LastModified:     function.LastModified,
+		LastUpdateStatus: function.LastUpdateStatus,
+		State:            function.State,
+		Environment:      function.Environment,
+		EphemeralStorage: function.EphemeralStorage,
+	}, nil
+}
+
+func (c *lambdaClient) UpdateFunctionCode(_ context.Context, params *lambda.UpdateFunctionCodeInput, _ ...func(*lambda.Options)) (*lambda.UpdateFunctionCodeOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := &c.b.fixtures.Functions[i]
+	if err := c.b.startFunctionUpdate(function, time.Now()); err != nil {
+		return nil, err
+	}
+	if len(params.Architectures) > 0 {
+		function.Architectures = params.Architectures
+	}
+
+	return &lambda.UpdateFunctionCodeOutput{
+		FunctionName: function.FunctionName,
+		FunctionArn:  function.FunctionArn,
+		LastModified: function.LastModified,
+	}, nil
+}
+
+func (c *lambdaClient) UpdateFunctionConfiguration(_ context.Context, params *lambda.UpdateFunctionConfigurationInput, _ ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := &c.b.fixtures.Functions[i]
+	if err := c.b.startFunctionUpdate(function, time.Now()); err != nil {
+		return nil, err
+	}
+	if params.Description != nil {
+		function.Description = params.Description
+	}
+	if params.MemorySize != nil {
+		function.MemorySize = params.MemorySize
+	}
+	if params.Timeout != nil {
+		function.Timeout = params.Timeout
+	}
+	if params.EphemeralStorage != nil {
+		function.EphemeralStorage = params.EphemeralStorage
+	}
+	if params.Environment != nil {
+		function.Environment = &lambdatypes.EnvironmentResponse{Variables: params.Environment.Variables}
+	}
+
+	return &lambda.UpdateFunctionConfigurationOutput{
+		FunctionName: function.FunctionName,
+		FunctionArn:  function.FunctionArn,
+		Description:  function.Description,
+		LastModified: function.LastModified,
+	}, nil
+}
+
+func (c *lambdaClient) TagResource(_ context.Context, params *lambda.TagResourceInput, _ ...func(*lambda.Options)) (*lambda.TagResourceOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.Resource))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.Resource), errNotFound)
+	}
+
+	name := *c.b.fixtures.Functions[i].FunctionName
+	if c.b.fixtures.FunctionTags[name] == nil {
+		c.b.fixtures.FunctionTags[name] = make(map[string]string)
+	}
+
+	for k, v := range params.Tags {
+		c.b.fixtures.FunctionTags[name][k] = v
+	}
+
+	return &lambda.TagResourceOutput{}, nil
+}
+
+func (c *lambdaClient) ListVersionsByFunction(_ context.Context, params *lambda.ListVersionsByFunctionInput, _ ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	latest := c.b.fixtures.Functions[i]
+	latest.Version = awssdk.String("$LATEST")
+
+	versions := []lambdatypes.FunctionConfiguration{latest}
+	versions = append(versions, c.b.fixtures.FunctionVersions[*latest.FunctionName]...)
+
+	return &lambda.ListVersionsByFunctionOutput{Versions: versions}, nil
+}
+
+func (c *lambdaClient) ListAliases(_ context.Context, params *lambda.ListAliasesInput, _ ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	aliases := make([]lambdatypes.AliasConfiguration, len(c.b.fixtures.FunctionAliases[*c.b.fixtures.Functions[i].FunctionName]))
+	copy(aliases, c.b.fixtures.FunctionAliases[*c.b.fixtures.Functions[i].FunctionName])
+
+	return &lambda.ListAliasesOutput{Aliases: aliases}, nil
+}
+
+func (c *lambdaClient) PublishVersion(_ context.Context, params *lambda.PublishVersionInput, _ ...func(*lambda.Options)) (*lambda.PublishVersionOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	c.b.completeFunctionUpdates(time.Now())
+
+	function := c.b.fixtures.Functions[i]
+	if function.LastUpdateStatus == lambdatypes.LastUpdateStatusInProgress {
+		return nil, fmt.Errorf("an update is in progress for function %s", *function.FunctionName)
+	}
+
+	published := c.b.fixtures.FunctionVersions[*function.FunctionName]
+
+	// like in AWS, nothing is published if the function has not changed since the last version
+	if n := len(published); n > 0 && awssdk.ToString(published[n-1].LastModified) == awssdk.ToString(function.LastModified) {
+		return publishVersionOutput(published[n-1]), nil
+	}
+
+	version := function
+	version.Version = awssdk.String(strconv.Itoa(len(published) + 1))
+	version.FunctionArn = awssdk.String(fmt.Sprintf("%s:%s", *function.FunctionArn, *version.Version))
+	if params.Description != nil {
+		version.Description = params.Description
+	}
+
+	c.b.fixtures.FunctionVersions[*function.FunctionName] = append(published, version)
+
+	return publishVersionOutput(version), nil
+}
+
+func publishVersionOutput(version lambdatypes.FunctionConfiguration) *lambda.PublishVersionOutput {
+	return &lambda.PublishVersionOutput{
+		FunctionName: version.FunctionName,
+		FunctionArn:  version.FunctionArn,
+		Version:      version.Version,
+		Description:  version.Description,
+		LastModified: version.LastModified,
+	}
+}
+
+func (c *lambdaClient) UpdateAlias(_ context.Context, params *lambda.UpdateAliasInput, _ ...func(*lambda.Options)) (*lambda.UpdateAliasOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	functionName := *c.b.fixtures.Functions[i].FunctionName
+	aliases := c.b.fixtures.FunctionAliases[functionName]
+
+	a := slices.IndexFunc(aliases, func(alias lambdatypes.AliasConfiguration) bool {
+		return awssdk.ToString(alias.Name) == awssdk.ToString(params.Name)
+	})
+	if a < 0 {
+		return nil, fmt.Errorf("alias %s of function %s: %w", awssdk.ToString(params.Name), functionName, errNotFound)
+	}
+
+	alias := &aliases[a]
+
+	functionVersion := awssdk.ToString(alias.FunctionVersion)
+	if params.FunctionVersion != nil {
+		if !c.b.isVersion(functionName, *params.FunctionVersion) {
+			return nil, fmt.Errorf("version %s of function %s: %w", *params.FunctionVersion, functionName, errNotFound)
+		}
+		functionVersion = *params.FunctionVersion
+	}
+
+	if params.RoutingConfig != nil {
+		for version, weight := range params.RoutingConfig.AdditionalVersionWeights {
+			if version == functionVersion || version == "$LATEST" || !c.b.isVersion(functionName, version) {
+				return nil, fmt.Errorf("invalid additional version %s for alias %s", version, *alias.Name)
+			}
+			if weight < 0 || weight > 1 {
+				return nil, fmt.Errorf("invalid weight %f for version %s, must be between 0.0 and 1.0", weight, version)
+			}
+		}
+	}
+
+	alias.FunctionVersion = awssdk.String(functionVersion)
+
+	if params.RoutingConfig != nil {
+		alias.RoutingConfig = nil
+		if len(params.RoutingConfig.AdditionalVersionWeights) > 0 {
+			alias.RoutingConfig = params.RoutingConfig
+		}
+	}
+
+	if params.Description != nil {
+		alias.Description = params.Description
+	}
+
+	return &lambda.UpdateAliasOutput{
+		AliasArn:        alias.AliasArn,
+		Name:            alias.Name,
+		FunctionVersion: alias.FunctionVersion,
+		Description:     alias.Description,
+		RoutingConfig:   alias.RoutingConfig,
+	}, nil
+}
+
+// Invoke runs a fake handler. The payload must be JSON, an event with an "error" key makes the function fail with
+// that message, any other event is answered with the keys it contains
+func (c *lambdaClient) Invoke(_ context.Context, params *lambda.InvokeInput, _ ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	// a reserved concurrency of 0 throttles every invocation
+	if reserved, found := c.b.fixtures.ReservedConcurrency[*c.b.fixtures.Functions[i].FunctionName]; found && reserved == 0 {
+		return nil, &lambdatypes.TooManyRequestsException{Message: awssdk.String("Rate Exceeded.")}
+	}
+
+	event := map[string]any{}
+	if len(params.Payload) > 0 {
+		if err := json.Unmarshal(params.Payload, &event); err != nil {
+			return nil, fmt.Errorf("could not parse request body into json: %w", err)
+		}
+	}
+
+	if params.InvocationType == lambdatypes.InvocationTypeEvent {
+		return &lambda.InvokeOutput{StatusCode: 202}, nil
+	}
+
+	function := c.b.fixtures.Functions[i]
+	version := awssdk.ToString(params.Qualifier)
+	if version == "" {
+		version = "$LATEST"
+	}
+
+	c.b.invocations++
+	requestId := fmt.Sprintf("c0ffee00-0000-4000-8000-%012d", c.b.invocations)
+
+	output := &lambda.InvokeOutput{
+		StatusCode:      200,
+		ExecutedVersion: awssdk.String(version),
+	}
+
+	var message string
+	if reason, found := event["error"]; found {
+		output.FunctionError = awssdk.String("Unhandled")
+		output.Payload, _ = json.Marshal(map[string]any{"errorType": "Error", "errorMessage": fmt.Sprint(reason)})
+		message = fmt.Sprintf("ERROR Invoke Error %s", output.Payload)
+	} else {
+		keys := slices.Sorted(maps.Keys(event))
+		output.Payload, _ = json.Marshal(map[string]any{"statusCode": 200, "body": fmt.Sprintf("handled event with keys %v", keys)})
+		message = fmt.Sprintf("INFO Handled event with %d keys", len(keys))
+	}
+
+	if params.LogType == lambdatypes.LogTypeTail {
+		tail := strings.Join([]string{
+			fmt.Sprintf("START RequestId: %s Version: %s", requestId, version),
+			fmt.Sprintf("%s\t%s\t%s", time.Now().UTC().Format("2006-01-02T15:04:05.000Z"), requestId, message),
+			fmt.Sprintf("END RequestId: %s", requestId),
+			fmt.Sprintf("REPORT RequestId: %s\tDuration: 12.34 ms\tBilled Duration: 13 ms\tMemory Size: %d MB\tMax Memory Used: 42 MB", requestId, awssdk.ToInt32(function.MemorySize)),
+			"",
+		}, "\n")
+		output.LogResult = awssdk.String(base64.StdEncoding.EncodeToString([]byte(tail)))
+	}
+
+	return output, nil
+}
+
+// functionUpdateDuration is how long a code or configuration update of a function is in progress
+const functionUpdateDuration = 2 * time.Second
+
+// startFunctionUpdate marks an update of the function as in progress. Like in AWS, a function cannot be updated
+// while a previous update is in progress
+func (b *Backend) startFunctionUpdate(function *lambdatypes.FunctionConfiguration, now time.Time) error {
+	if function.LastUpdateStatus == lambdatypes.LastUpdateStatusInProgress {
+		return fmt.Errorf("an update is in progress for function %s", awssdk.ToString(function.FunctionName))
+	}
+
+	function.LastModified = awssdk.String(now.UTC().Format("2006-01-02T15:04:05.000-0700"))
+	function.LastUpdateStatus = lambdatypes.LastUpdateStatusInProgress
+	b.functionUpdates[awssdk.ToString(function.FunctionName)] = now.Add(functionUpdateDuration)
+
+	return nil
+}
+
+// completeFunctionUpdates marks the updates that have been in progress long enough as successful
+func (b *Backend) completeFunctionUpdates(now time.Time) {
+	for name, done := range b.functionUpdates {
+		if now.Before(done) {
+			continue
+		}
+		if i := b.findFunction(name); i >= 0 {
+			b.fixtures.Functions[i].LastUpdateStatus = lambdatypes.LastUpdateStatusSuccessful
+		}
+		delete(b.functionUpdates, name)
+	}
+}
+
+// isVersion returns true if version is $LATEST or a published version of the function
+func (b *Backend) isVersion(functionName, version string) bool {
+	if version == "$LATEST" {
+		return true
+	}
+	return slices.ContainsFunc(b.fixtures.FunctionVersions[functionName], func(v lambdatypes.FunctionConfiguration) bool {
+		return awssdk.ToString(v.Version) == version
+	})
+}
+
+func (c *lambdaClient) UntagResource(_ context.Context, params *lambda.UntagResourceInput, _ ...func(*lambda.Options)) (*lambda.UntagResourceOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.Resource))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.Resource), errNotFound)
+	}
+
+	for _, key := range params.TagKeys {
+		delete(c.b.fixtures.FunctionTags[*c.b.fixtures.Functions[i].FunctionName], key)
+	}
+
+	return &lambda.UntagResourceOutput{}, nil
+}
+
+func (c *lambdaClient) PutFunctionConcurrency(_ context.Context, params *lambda.PutFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	reserved := awssdk.ToInt32(params.ReservedConcurrentExecutions)
+	if reserved < 0 {
+		return nil, fmt.Errorf("invalid reserved concurrency %d", reserved)
+	}
+	c.b.fixtures.ReservedConcurrency[*c.b.fixtures.Functions[i].FunctionName] = reserved
+
+	return &lambda.PutFunctionConcurrencyOutput{ReservedConcurrentExecutions: awssdk.Int32(reserved)}, nil
+}
+
+func (c *lambdaClient) DeleteFunctionConcurrency(_ context.Context, params *lambda.DeleteFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	delete(c.b.fixtures.ReservedConcurrency, *c.b.fixtures.Functions[i].FunctionName)
+
+	return &lambda.DeleteFunctionConcurrencyOutput{}, nil
+}
+
+func (c *lambdaClient) ListProvisionedConcurrencyConfigs(_ context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput, _ ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	configs := slices.Clone(c.b.fixtures.ProvisionedConcurrency[*c.b.fixtures.Functions[i].FunctionName])
+
+	return &lambda.ListProvisionedConcurrencyConfigsOutput{ProvisionedConcurrencyConfigs: configs}, nil
+}
+
+func (c *lambdaClient) PutProvisionedConcurrencyConfig(_ context.Context, params *lambda.PutProvisionedConcurrencyConfigInput, _ ...func(*lambda.Options)) (*lambda.PutProvisionedConcurrencyConfigOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := c.b.fixtures.Functions[i]
+	qualifier := awssdk.ToString(params.Qualifier)
+	if qualifier == "$LATEST" || !(c.b.isVersion(*function.FunctionName, qualifier) || c.b.isAlias(*function.FunctionName, qualifier)) {
+		return nil, fmt.Errorf("provisioned concurrency needs a published version or an alias, not %q", qualifier)
+	}
+
+	requested := awssdk.ToInt32(params.ProvisionedConcurrentExecutions)
+	config := lambdatypes.ProvisionedConcurrencyConfigListItem{
+		FunctionArn:                              awssdk.String(fmt.Sprintf("%s:%s", *function.FunctionArn, qualifier)),
+		RequestedProvisionedConcurrentExecutions: awssdk.Int32(requested),
+		AllocatedProvisionedConcurrentExecutions: awssdk.Int32(requested),
+		AvailableProvisionedConcurrentExecutions: awssdk.Int32(requested),
+		Status:                                   lambdatypes.ProvisionedConcurrencyStatusEnumReady,
+		LastModified:                             awssdk.String(time.Now().UTC().Format("2006-01-02T15:04:05.000-0700")),
+	}
+
+	configs := slices.DeleteFunc(c.b.fixtures.ProvisionedConcurrency[*function.FunctionName], func(item lambdatypes.ProvisionedConcurrencyConfigListItem) bool {
+		return awssdk.ToString(item.FunctionArn) == *config.FunctionArn
+	})
+	c.b.fixtures.ProvisionedConcurrency[*function.FunctionName] = append(configs, config)
+
+	return &lambda.PutProvisionedConcurrencyConfigOutput{
+		RequestedProvisionedConcurrentExecutions: config.RequestedProvisionedConcurrentExecutions,
+		AllocatedProvisionedConcurrentExecutions: config.AllocatedProvisionedConcurrentExecutions,
+		AvailableProvisionedConcurrentExecutions: config.AvailableProvisionedConcurrentExecutions,
+		Status:                                   config.Status,
+		LastModified:                             config.LastModified,
+	}, nil
+}
+
+func (c *lambdaClient) DeleteProvisionedConcurrencyConfig(_ context.Context, params *lambda.DeleteProvisionedConcurrencyConfigInput, _ ...func(*lambda.Options)) (*lambda.DeleteProvisionedConcurrencyConfigOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	i := c.b.findFunction(awssdk.ToString(params.FunctionName))
+	if i < 0 {
+		return nil, fmt.Errorf("function %s: %w", awssdk.ToString(params.FunctionName), errNotFound)
+	}
+
+	function := c.b.fixtures.Functions[i]
+	arn := fmt.Sprintf("%s:%s", *function.FunctionArn, awssdk.ToString(params.Qualifier))
+
+	configs := c.b.fixtures.ProvisionedConcurrency[*function.FunctionName]
+	remaining := slices.DeleteFunc(slices.Clone(configs), func(item lambdatypes.ProvisionedConcurrencyConfigListItem) bool {
+		return awssdk.ToString(item.FunctionArn) == arn
+	})
+	if len(remaining) == len(configs) {
+		return nil, fmt.Errorf("provisioned concurrency of %s: %w", arn, errNotFound)
+	}
+	c.b.fixtures.ProvisionedConcurrency[*function.FunctionName] = remaining
+
+	return &lambda.DeleteProvisionedConcurrencyConfigOutput{}, nil
+}
+
+// isAlias returns true if the function has an alias with the name
+func (b *Backend) isAlias(functionName, name string) bool {
+	return slices.ContainsFunc(b.fixtures.FunctionAliases[functionName], func(a lambdatypes.AliasConfiguration) bool {
+		return awssdk.ToString(a.Name) == name
+	})
+}
+
+// findFunction returns the index of the function identified by name or arn, or -1 if not found
+func (b *Backend) findFunction(identifier string) int {
+	for i, v := range b.fixtures.Functions {
+		if identifier == awssdk.ToString(v.FunctionName) || matchesName(identifier, v.FunctionArn) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,235 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// tailInterval is how often the fake live tail emits a new log event
+const tailInterval = time.Second
+
+// maxFilteredEvents is the number of events generated for a filter log events request
+const maxFilteredEvents = 360
+
+type logsClient struct {
+	b *Backend
+}
+
+func (c *logsClient) DescribeLogStreams(_ context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	now := time.Now().UnixMilli()
+
+	return &cloudwatchlogs.DescribeLogStreamsOutput{
+		LogStreams: []logstypes.LogStream{
+			{
+				LogStreamName:      awssdk.String(fmt.Sprintf("%s/main", awssdk.ToString(params.LogGroupName))),
+				LastEventTimestamp: awssdk.Int64(now),
+			},
+		},
+	}, nil
+}
+
+func (c *logsClient) GetLogEvents(_ context.Context, params *cloudwatchlogs.GetLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	// The same token is returned to signal that there are no more events
+	token := awssdk.String("f/fake")
+	if params.NextToken != nil {
+		return &cloudwatchlogs.GetLogEventsOutput{NextForwardToken: token}, nil
+	}
+
+	return &cloudwatchlogs.GetLogEventsOutput{
+		Events:           c.b.logEvents(time.Now().Add(-time.Hour), time.Now(), time.Minute),
+		NextForwardToken: token,
+	}, nil
+}
+
+func (c *logsClient) FilterLogEvents(_ context.Context, params *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	end := time.Now()
+	if params.EndTime != nil {
+		end = time.UnixMilli(*params.EndTime)
+	}
+	start := end.Add(-time.Hour)
+	if params.StartTime != nil {
+		start = time.UnixMilli(*params.StartTime)
+	}
+
+	// at most maxFilteredEvents events are generated for the period
+	interval := end.Sub(start) / maxFilteredEvents
+	if interval < time.Second {
+		interval = time.Second
+	}
+	interval = interval.Truncate(time.Second)
+
+	pattern := awssdk.ToString(params.FilterPattern)
+	if err := validatePattern(pattern); err != nil {
+		return nil, err
+	}
+
+	events := make([]logstypes.FilteredLogEvent, 0)
+	for _, v := range c.b.logEvents(start, end, interval) {
+		if v.Timestamp == nil || *v.Timestamp < start.UnixMilli() {
+			continue
+		}
+		if !matchesPattern(pattern, awssdk.ToString(v.Message)) {
+			continue
+		}
+
+		events = append(events, logstypes.FilteredLogEvent{
+			EventId:       awssdk.String(fmt.Sprint(*v.Timestamp)),
+			LogStreamName: awssdk.String("main"),
+			Message:       v.Message,
+			Timestamp:     v.Timestamp,
+			IngestionTime: v.IngestionTime,
+		})
+	}
+
+	return &cloudwatchlogs.FilterLogEventsOutput{Events: events}, nil
+}
+
+func (c *logsClient) TailLogs(_ context.Context, params *cloudwatchlogs.StartLiveTailInput) (*cloudwatchlogs.StartLiveTailEventStream, error) {
+	if err := validatePattern(awssdk.ToString(params.LogEventFilterPattern)); err != nil {
+		return nil, err
+	}
+
+	reader := newTailReader(c.b, params)
+
+	return cloudwatchlogs.NewStartLiveTailEventStream(func(stream *cloudwatchlogs.StartLiveTailEventStream) {
+		stream.Reader = reader
+	}), nil
+}
+
+// logEvents generates log events between start and end, one per interval, cycling through the fixture messages
+func (b *Backend) logEvents(start, end time.Time, interval time.Duration) []logstypes.OutputLogEvent {
+	b.mu.Lock()
+	messages := b.fixtures.LogMessages
+	b.mu.Unlock()
+
+	events := make([]logstypes.OutputLogEvent, 0)
+	if len(messages) == 0 {
+		return events
+	}
+
+	for when := start.Truncate(interval); !when.After(end); when = when.Add(interval) {
+		message := messages[int(when.Unix()/int64(interval.Seconds()))%len(messages)]
+		events = append(events, logstypes.OutputLogEvent{
+			Message:       awssdk.String(formatMessage(message, when)),
+			Timestamp:     awssdk.Int64(when.UnixMilli()),
+			IngestionTime: awssdk.Int64(when.UnixMilli()),
+		})
+	}
+
+	return events
+}
+
+// formatMessage replaces the {{timestamp}} placeholder in a fixture message with the timestamp of the event
+func formatMessage(message string, when time.Time) string {
+	return strings.ReplaceAll(message, "{{timestamp}}", when.UTC().Format("2006-01-02T15:04:05.000Z"))
+}
+
+// validatePattern rejects patterns with unbalanced braces or quotes, the way CloudWatch rejects invalid patterns
+func validatePattern(pattern string) error {
+	if strings.Count(pattern, "{") != strings.Count(pattern, "}") || strings.Count(pattern, `"`)%2 != 0 {
+		return &logstypes.InvalidParameterException{Message: awssdk.String(fmt.Sprintf("Invalid filter pattern: %s", pattern))}
+	}
+	return nil
+}
+
+// matchesPattern matches a filter pattern as plain text, ignoring quotes. An empty pattern matches every message
+func matchesPattern(pattern, message string) bool {
+	pattern = strings.Trim(pattern, `"`)
+	return pattern == "" || strings.Contains(message, pattern)
+}
+
+// tailReader implements the live tail event stream reader, emitting a session start event followed by a
+// session update with a single log event every tailInterval until closed
+type tailReader struct {
+	b      *Backend
+	params *cloudwatchlogs.StartLiveTailInput
+	events chan logstypes.StartLiveTailResponseStream
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newTailReader(b *Backend, params *cloudwatchlogs.StartLiveTailInput) *tailReader {
+	reader := &tailReader{
+		b:      b,
+		params: params,
+		events: make(chan logstypes.StartLiveTailResponseStream),
+		done:   make(chan struct{}),
+	}
+
+	go reader.run()
+
+	return reader
+}
+
+func (r *tailReader) run() {
+	defer close(r.events)
+
+	start := &logstypes.StartLiveTailResponseStreamMemberSessionStart{
+		Value: logstypes.LiveTailSessionStart{
+			SessionId:           awssdk.String("fake-session"),
+			LogGroupIdentifiers: r.params.LogGroupIdentifiers,
+		},
+	}
+
+	select {
+	case r.events <- start:
+	case <-r.done:
+		return
+	}
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			results := make([]logstypes.LiveTailSessionLogEvent, 0)
+			for _, v := range r.b.logEvents(now, now, tailInterval) {
+				if !matchesPattern(awssdk.ToString(r.params.LogEventFilterPattern), awssdk.ToString(v.Message)) {
+					continue
+				}
+				results = append(results, logstypes.LiveTailSessionLogEvent{
+					Message:            v.Message,
+					Timestamp:          v.Timestamp,
+					IngestionTime:      v.IngestionTime,
+					LogGroupIdentifier: awssdk.String(r.params.LogGroupIdentifiers[0]),
+					LogStreamName:      awssdk.String("main"),
+				})
+			}
+
+			update := &logstypes.StartLiveTailResponseStreamMemberSessionUpdate{
+				Value: logstypes.LiveTailSessionUpdate{SessionResults: results},
+			}
+
+			select {
+			case r.events <- update:
+			case <-r.done:
+				return
+			}
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *tailReader) Events() <-chan logstypes.StartLiveTailResponseStream {
+	return r.events
+}
+
+func (r *tailReader) Close() error {
+	r.once.Do(func() {
+		close(r.done)
+	})
+	return nil
+}
+
+func (r *tailReader) Err() error {
+	return nil
+}
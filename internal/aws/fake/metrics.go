@@ -0,0 +1,70 @@
+package fake
+
+import (
+	"math"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// metricScale is the maximum value generated for well known metrics. Metrics not listed use a scale of 100
+var metricScale = map[string]float64{
+	"CpuUtilized":               1024,
+	"MemoryUtilized":            2048,
+	"Invocations":               250,
+	"Errors":                    6,
+	"Throttles":                 2,
+	"Duration":                  450,
+	"ConcurrentExecutions":      12,
+	"RunningTaskCount":          3,
+	"RequestCount":              900,
+	"HTTPCode_Target_5XX_Count": 4,
+	"TargetResponseTime":        0.35,
+}
+
+// metricConstant lists metrics that do not vary over time
+var metricConstant = map[string]float64{
+	"CpuReserved":    1024,
+	"MemoryReserved": 2048,
+}
+
+const maxDataPoints = 1440
+
+// metricDataResult generates data points for a metric between start and end, newest first as returned by CloudWatch
+func metricDataResult(id, metricName string, start, end time.Time, period time.Duration) cloudwatchtypes.MetricDataResult {
+	result := cloudwatchtypes.MetricDataResult{
+		Id:         awssdk.String(id),
+		Label:      awssdk.String(metricName),
+		StatusCode: cloudwatchtypes.StatusCodeComplete,
+	}
+
+	if period <= 0 || !end.After(start) {
+		return result
+	}
+
+	scale, found := metricScale[metricName]
+	if !found {
+		scale = 100
+	}
+
+	seed := seedOf(id + metricName)
+
+	when := end.Truncate(period)
+	for i := 0; i < maxDataPoints && !when.Before(start); i++ {
+		value, constant := metricConstant[metricName]
+		if !constant {
+			value = scale * wave(when, seed)
+			if scale >= 2 {
+				value = math.Round(value)
+			}
+		}
+
+		result.Timestamps = append(result.Timestamps, when)
+		result.Values = append(result.Values, value)
+
+		when = when.Add(-period)
+	}
+
+	return result
+}
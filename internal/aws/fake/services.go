@@ -0,0 +1,270 @@
+package fake
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type ecrClient struct {
+	b *Backend
+}
+
+func (c *ecrClient) DescribeImages(_ context.Context, params *ecr.DescribeImagesInput, _ ...func(*ecr.Options)) (*ecr.DescribeImagesOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	images := make([]ecrtypes.ImageDetail, 0)
+	for _, v := range c.b.fixtures.Images {
+		if awssdk.ToString(v.RepositoryName) == awssdk.ToString(params.RepositoryName) {
+			images = append(images, v)
+		}
+	}
+
+	return &ecr.DescribeImagesOutput{ImageDetails: images}, nil
+}
+
+type s3Client struct {
+	b *Backend
+}
+
+func (c *s3Client) ListObjectsV2(_ context.Context, params *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	if c.b.fixtures.Bucket != "" && awssdk.ToString(params.Bucket) != c.b.fixtures.Bucket {
+		return nil, &s3types.NoSuchBucket{Message: params.Bucket}
+	}
+
+	objects := make([]s3types.Object, 0)
+	for _, v := range c.b.fixtures.Objects {
+		if strings.HasPrefix(awssdk.ToString(v.Key), awssdk.ToString(params.Prefix)) {
+			objects = append(objects, v)
+		}
+	}
+
+	return &s3.ListObjectsV2Output{
+		Contents: objects,
+		KeyCount: awssdk.Int32(int32(len(objects))),
+	}, nil
+}
+
+type ssmClient struct {
+	b *Backend
+}
+
+func (c *ssmClient) GetParameter(_ context.Context, params *ssm.GetParameterInput, _ ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	value, found := c.b.fixtures.Parameters[awssdk.ToString(params.Name)]
+	if !found {
+		return nil, &ssmtypes.ParameterNotFound{Message: params.Name}
+	}
+
+	return &ssm.GetParameterOutput{
+		Parameter: &ssmtypes.Parameter{
+			Name:  params.Name,
+			Value: awssdk.String(value),
+		},
+	}, nil
+}
+
+func (c *ssmClient) PutParameter(_ context.Context, params *ssm.PutParameterInput, _ ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	name := awssdk.ToString(params.Name)
+	if _, found := c.b.fixtures.Parameters[name]; found && !awssdk.ToBool(params.Overwrite) {
+		return nil, &ssmtypes.ParameterAlreadyExists{Message: params.Name}
+	}
+
+	c.b.fixtures.Parameters[name] = awssdk.ToString(params.Value)
+
+	now := time.Now()
+	version := int64(len(c.b.fixtures.ParameterHistory[name]) + 1)
+	c.b.fixtures.ParameterHistory[name] = append(c.b.fixtures.ParameterHistory[name], ssmtypes.ParameterHistory{
+		Name:             params.Name,
+		Value:            params.Value,
+		Version:          version,
+		LastModifiedDate: &now,
+		LastModifiedUser: awssdk.String(c.b.callerArn()),
+	})
+
+	return &ssm.PutParameterOutput{Version: version}, nil
+}
+
+func (c *ssmClient) GetParameterHistory(_ context.Context, params *ssm.GetParameterHistoryInput, _ ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	history, found := c.b.fixtures.ParameterHistory[awssdk.ToString(params.Name)]
+	if !found {
+		return nil, &ssmtypes.ParameterNotFound{Message: params.Name}
+	}
+
+	return &ssm.GetParameterHistoryOutput{Parameters: append([]ssmtypes.ParameterHistory{}, history...)}, nil
+}
+
+type stsClient struct {
+	b *Backend
+}
+
+func (c *stsClient) GetCallerIdentity(_ context.Context, _ *sts.GetCallerIdentityInput, _ ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{
+		Account: awssdk.String(c.b.fixtures.AccountId),
+		Arn:     awssdk.String(c.b.callerArn()),
+		UserId:  awssdk.String("S9KFAKEUSER"),
+	}, nil
+}
+
+var (
+	searchMetricName    = regexp.MustCompile(`MetricName="([^"]+)"`)
+	searchStatAndPeriod = regexp.MustCompile(`'(\w+)',\s*(\d+)\)`)
+)
+
+type cloudwatchClient struct {
+	b *Backend
+}
+
+// GetMetricData returns generated data points for every query, one per period between start and end time.
+// Reserved cpu and memory are constant, all other metrics follow a wave
+func (c *cloudwatchClient) GetMetricData(_ context.Context, params *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	output := &cloudwatch.GetMetricDataOutput{}
+
+	start := awssdk.ToTime(params.StartTime)
+	end := awssdk.ToTime(params.EndTime)
+
+	for _, query := range params.MetricDataQueries {
+		period := int32(60)
+		metricName := ""
+		stat := ""
+		dimensions := ""
+		if query.MetricStat != nil {
+			stat = awssdk.ToString(query.MetricStat.Stat)
+			if query.MetricStat.Period != nil {
+				period = *query.MetricStat.Period
+			}
+			if query.MetricStat.Metric != nil {
+				metricName = awssdk.ToString(query.MetricStat.Metric.MetricName)
+				for _, dimension := range query.MetricStat.Metric.Dimensions {
+					dimensions += awssdk.ToString(dimension.Value)
+				}
+			}
+		}
+		if query.Period != nil {
+			period = *query.Period
+		}
+
+		// metric math is not evaluated, the data follows the metric, statistic and period of the search in the
+		// expression
+		if query.Expression != nil {
+			expression := awssdk.ToString(query.Expression)
+			if match := searchMetricName.FindStringSubmatch(expression); match != nil {
+				metricName = match[1]
+			}
+			if match := searchStatAndPeriod.FindStringSubmatch(expression); match != nil {
+				stat = match[1]
+				if seconds, err := strconv.Atoi(match[2]); err == nil {
+					period = int32(seconds)
+				}
+			}
+			dimensions = expression
+		}
+
+		// the dimensions are part of the seed, so the metrics of different resources look different
+		result := metricDataResult(awssdk.ToString(query.Id)+dimensions, metricName, start, end, time.Duration(period)*time.Second)
+
+		// percentiles of a metric follow the same wave, scaled so higher percentiles have higher values
+		if percentile, err := strconv.ParseFloat(strings.TrimPrefix(stat, "p"), 64); strings.HasPrefix(stat, "p") && err == nil {
+			result = metricDataResult(dimensions, metricName, start, end, time.Duration(period)*time.Second)
+			for i := range result.Values {
+				result.Values[i] = math.Round(result.Values[i] * percentile / 50)
+			}
+		}
+		result.Id = query.Id
+
+		// the scale of counts is per minute, longer periods have larger sums
+		if stat == "Sum" {
+			for i := range result.Values {
+				result.Values[i] *= float64(period) / 60
+			}
+		}
+
+		output.MetricDataResults = append(output.MetricDataResults, result)
+	}
+
+	return output, nil
+}
+
+type apigatewayClient struct {
+	b *Backend
+}
+
+func (c *apigatewayClient) GetRestApis(_ context.Context, _ *apigateway.GetRestApisInput, _ ...func(*apigateway.Options)) (*apigateway.GetRestApisOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	return &apigateway.GetRestApisOutput{Items: c.b.fixtures.RestApis}, nil
+}
+
+type apigatewayv2Client struct {
+	b *Backend
+}
+
+func (c *apigatewayv2Client) GetApis(_ context.Context, _ *apigatewayv2.GetApisInput, _ ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	return &apigatewayv2.GetApisOutput{Items: c.b.fixtures.HttpApis}, nil
+}
+
+func (c *apigatewayv2Client) GetDomainNames(_ context.Context, _ *apigatewayv2.GetDomainNamesInput, _ ...func(*apigatewayv2.Options)) (*apigatewayv2.GetDomainNamesOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	return &apigatewayv2.GetDomainNamesOutput{Items: c.b.fixtures.DomainNames}, nil
+}
+
+func (c *apigatewayv2Client) GetApiMappings(_ context.Context, params *apigatewayv2.GetApiMappingsInput, _ ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApiMappingsOutput, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+
+	return &apigatewayv2.GetApiMappingsOutput{Items: c.b.fixtures.ApiMappings[awssdk.ToString(params.DomainName)]}, nil
+}
+
+func (c *apigatewayv2Client) GetStage(_ context.Context, params *apigatewayv2.GetStageInput, _ ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStageOutput, error) {
+	return &apigatewayv2.GetStageOutput{
+		StageName: params.StageName,
+	}, nil
+}
+
+// wave returns a value between 0 and 1 that varies smoothly over time, offset by the given seed
+func wave(when time.Time, seed int) float64 {
+	x := float64(when.Unix())/900 + float64(seed)
+	return (math.Sin(x)+1)/2*0.7 + (math.Sin(x*3.7)+1)/2*0.3
+}
+
+// seedOf returns a small number derived from the given text, used to make different metrics look different
+func seedOf(text string) int {
+	seed := 0
+	for _, r := range text {
+		seed += int(r)
+	}
+	return seed % 17
+}
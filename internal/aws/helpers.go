@@ -2,33 +2,36 @@ package aws
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"os"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/apigateway"
-	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/applicationautoscaling"
+	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/applicationautoscaling/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	awscloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
-	"github.com/aws/aws-sdk-go-v2/service/ecr"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/oslokommune/common-lib-go/aws/awsapigateway"
 	"github.com/oslokommune/common-lib-go/aws/awsapigatewayv2"
 	awscloudwatch "github.com/oslokommune/common-lib-go/aws/awscloudwatch"
 	"github.com/oslokommune/common-lib-go/aws/awsecr"
 	"github.com/oslokommune/common-lib-go/aws/awsecs"
 	"github.com/oslokommune/common-lib-go/aws/awslambda"
-	awsssm "github.com/oslokommune/common-lib-go/aws/awsparameterstore"
 	"github.com/oslokommune/common-lib-go/aws/awss3"
 	"github.com/rs/zerolog/log"
 
@@ -47,45 +50,41 @@ type LogItem struct {
 	Updated time.Time `json:"updated,omitempty"`
 	User    string    `json:"user,omitempty"`
 	Version string    `json:"version,omitempty"`
+	// Revision is the task definition revision of a service deployment, e.g. api:12
+	Revision string `json:"revision,omitempty"`
+	// Source is where the item was found, e.g. ssm or ecs
+	Source string `json:"source,omitempty"`
 }
 
-const S3_BUCKET_VAR_NAME = "S3_DEPLOYMENT_BUCKET_NAME"
+// Tags set on a lambda function when a version is deployed
+const (
+	LastDeployedTag   = "LastDeployed"
+	LastDeployedByTag = "LastDeployedBy"
+	LastDeployedAtTag = "LastDeployedAt"
+)
 
 var (
-	ecsClient            *ecs.Client
-	ecrClient            *ecr.Client
-	s3Client             *s3.Client
-	stsClient            *sts.Client
-	lambdaClient         *lambda.Client
-	ssmClient            *ssm.Client
-	apigatewayv2Client   *apigatewayv2.Client
-	apigatewayClient     *apigateway.Client
-	cloudwatchClient     *cloudwatch.Client
-	cloudwatchLogsClient *cloudwatchlogs.Client
-	s3_bucket_name       string
+	ecsClient            ECSProvider
+	ecrClient            ECRProvider
+	s3Client             S3Provider
+	stsClient            STSProvider
+	lambdaClient         LambdaProvider
+	ssmClient            SSMProvider
+	apigatewayv2Client   APIGatewayV2Provider
+	apigatewayClient     APIGatewayProvider
+	cloudwatchClient     CloudWatchProvider
+	cloudwatchLogsClient CloudWatchLogsProvider
+	autoScalingClient    ApplicationAutoScalingProvider
+	currentProfile       string
+	currentRegion        string
 )
 
 func init() {
-	cfg, _ := config.LoadDefaultConfig(context.TODO())
-
-	value, found := os.LookupEnv(S3_BUCKET_VAR_NAME)
-	if !found {
-		fmt.Printf("The %s environment variable is not set.\nIt must be set to the parent directory where your lambda function zip files are stored.\n", S3_BUCKET_VAR_NAME)
+	// configures clients from the default profile and region
+	err := Configure("", "")
+	if err != nil {
+		fmt.Printf("Failed to load AWS configuration: %v\n", err)
 	}
-
-	s3_bucket_name = value
-
-	// configures clients
-	ecsClient = ecs.NewFromConfig(cfg)
-	ecrClient = ecr.NewFromConfig(cfg)
-	apigatewayv2Client = apigatewayv2.NewFromConfig(cfg)
-	apigatewayClient = apigateway.NewFromConfig(cfg)
-	lambdaClient = lambda.NewFromConfig(cfg)
-	s3Client = s3.NewFromConfig(cfg)
-	stsClient = sts.NewFromConfig(cfg)
-	ssmClient = ssm.NewFromConfig(cfg)
-	cloudwatchClient = cloudwatch.NewFromConfig(cfg)
-	cloudwatchLogsClient = cloudwatchlogs.NewFromConfig(cfg)
 }
 
 // FetchApis reads apigateway apis
@@ -176,17 +175,263 @@ func FetchApis() []ApiGateway {
 	return values
 }
 
-// FetchCpuAndMemoryUsage reads memory and cpu usage from cloudwatch metrics for a given ECS service. Service and cluster name must be provided.
-func FetchCpuAndMemoryUsage(serviceName, clusterName string) (memoryUtilized uint32, memoryReserved uint32, cpuUtilized uint32, cpuReserved uint32, err error) {
-	memoryUtilized, memoryReserved, cpuUtilized, cpuReserved, err = awscloudwatch.FetchCpuAndMemoryUsage(context.TODO(), serviceName, clusterName, cloudwatchClient)
+// lambdaMetrics are the metrics read for a lambda function, with the statistic read for each
+var lambdaMetrics = []struct{ label, metricName, stat, unit string }{
+	{"Invocations", "Invocations", "Sum", ""},
+	{"Errors", "Errors", "Sum", ""},
+	{"Throttles", "Throttles", "Sum", ""},
+	{"Duration p50", "Duration", "p50", "ms"},
+	{"Duration p99", "Duration", "p99", "ms"},
+	{"Concurrent executions", "ConcurrentExecutions", "Maximum", ""},
+}
+
+// errorRatePeriod is the period of the metrics summed to get the error rate of lambda functions
+const errorRatePeriod = 5 * time.Minute
+
+// FetchLambdaMetrics reads the invocations, errors, throttles, duration and concurrent executions of a lambda
+// function for the window up to now, with about 60 values in each series
+func FetchLambdaMetrics(functionName string, window time.Duration) ([]MetricSeries, error) {
+	period := metricPeriod(window)
+	end := time.Now().Truncate(period)
+	start := end.Add(-window)
+
+	queries := make([]cloudwatchtypes.MetricDataQuery, 0, len(lambdaMetrics))
+	for i, metric := range lambdaMetrics {
+		queries = append(queries, lambdaMetricQuery(fmt.Sprintf("m%d", i), functionName, metric.metricName, metric.stat, period))
+	}
+
+	results, err := fetchMetricData(queries, start, end)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to retrieve metrics for ecs service")
+		return nil, err
 	}
-	return
+
+	series := make([]MetricSeries, 0, len(lambdaMetrics))
+	for i, metric := range lambdaMetrics {
+		s := newMetricSeries(metric.label, metric.stat, results[fmt.Sprintf("m%d", i)], start, end, period)
+		s.Unit = metric.unit
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
+// loadBalancerMetrics are the metrics read for the target groups of a service, with the statistic read for each and
+// the function combining the target groups
+var loadBalancerMetrics = []struct{ label, metricName, stat, combine, unit string }{
+	{"Requests", "RequestCount", "Sum", "SUM", ""},
+	{"5xx responses", "HTTPCode_Target_5XX_Count", "Sum", "SUM", ""},
+	{"Target response time", "TargetResponseTime", "Average", "AVG", "s"},
 }
 
-func FetchTailLogsChannel(logGroupArn string) (*cloudwatchlogs.StartLiveTailEventStream, error) {
-	output, err := awscloudwatch.TailLogs(context.Background(), logGroupArn, cloudwatchLogsClient)
+// FetchServiceMetrics reads the cpu and memory utilization and the running tasks of an ECS service from Container
+// Insights for the window up to now. When target groups are given, the requests, 5xx responses and response time of
+// the target groups are read as well
+func FetchServiceMetrics(clusterName, serviceName string, targetGroupArns []string, window time.Duration) ([]MetricSeries, error) {
+	period := metricPeriod(window)
+	end := time.Now().Truncate(period)
+	start := end.Add(-window)
+
+	containerInsights := []string{"CpuUtilized", "CpuReserved", "MemoryUtilized", "MemoryReserved", "RunningTaskCount"}
+
+	queries := []cloudwatchtypes.MetricDataQuery{}
+	for _, metricName := range containerInsights {
+		queries = append(queries, cloudwatchtypes.MetricDataQuery{
+			Id: aws.String(strings.ToLower(metricName)),
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  aws.String("ECS/ContainerInsights"),
+					MetricName: aws.String(metricName),
+					Dimensions: []cloudwatchtypes.Dimension{
+						{Name: aws.String("ClusterName"), Value: aws.String(clusterName)},
+						{Name: aws.String("ServiceName"), Value: aws.String(serviceName)},
+					},
+				},
+				Period: aws.Int32(int32(period.Seconds())),
+				Stat:   aws.String("Average"),
+			},
+		})
+	}
+
+	// the load balancer of a target group is not known from the service, so the metrics of the target groups are
+	// searched for and combined
+	targetGroups := make([]string, 0, len(targetGroupArns))
+	for _, arn := range targetGroupArns {
+		// arn:aws:elasticloadbalancing:<region>:<account>:targetgroup/<name>/<id>
+		targetGroups = append(targetGroups, fmt.Sprintf("TargetGroup=%q", arn[strings.LastIndex(arn, ":")+1:]))
+	}
+	if len(targetGroups) > 0 {
+		for i, metric := range loadBalancerMetrics {
+			search := fmt.Sprintf("SEARCH('{AWS/ApplicationELB,LoadBalancer,TargetGroup} MetricName=%q (%s)', '%s', %d)",
+				metric.metricName, strings.Join(targetGroups, " OR "), metric.stat, int(period.Seconds()))
+			queries = append(queries, cloudwatchtypes.MetricDataQuery{
+				Id:         aws.String(fmt.Sprintf("lb%d", i)),
+				Expression: aws.String(fmt.Sprintf("%s(%s)", metric.combine, search)),
+			})
+		}
+	}
+
+	results, err := fetchMetricData(queries, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	series := []MetricSeries{
+		utilization("CPU utilization", results["cpuutilized"], results["cpureserved"], start, end, period),
+		utilization("Memory utilization", results["memoryutilized"], results["memoryreserved"], start, end, period),
+		newMetricSeries("Running tasks", "Average", results["runningtaskcount"], start, end, period),
+	}
+
+	if len(targetGroups) > 0 {
+		for i, metric := range loadBalancerMetrics {
+			s := newMetricSeries(metric.label, metric.stat, results[fmt.Sprintf("lb%d", i)], start, end, period)
+			s.Unit = metric.unit
+			series = append(series, s)
+		}
+	}
+
+	return series, nil
+}
+
+// utilization returns the utilized share of the reserved capacity in percent
+func utilization(label string, utilized, reserved cloudwatchtypes.MetricDataResult, start, end time.Time, period time.Duration) MetricSeries {
+	series := newMetricSeries(label, "Average", utilized, start, end, period)
+	series.Unit = "%"
+
+	capacity := newMetricSeries(label, "Average", reserved, start, end, period)
+	for i := range series.Values {
+		if capacity.Values[i] > 0 {
+			series.Values[i] = series.Values[i] / capacity.Values[i] * 100
+		} else {
+			series.Values[i] = 0
+		}
+	}
+
+	return series
+}
+
+// FetchLambdaErrorRates returns the percentage of failed invocations of each function in the window up to now.
+// Functions without invocations in the window are left out
+func FetchLambdaErrorRates(functionNames []string, window time.Duration) (map[string]float64, error) {
+	end := time.Now().Truncate(errorRatePeriod)
+	start := end.Add(-window)
+
+	rates := map[string]float64{}
+
+	// GetMetricData takes at most 500 queries, two are needed for each function
+	for chunk := range slices.Chunk(functionNames, 250) {
+		queries := make([]cloudwatchtypes.MetricDataQuery, 0, 2*len(chunk))
+		for i, functionName := range chunk {
+			queries = append(queries,
+				lambdaMetricQuery(fmt.Sprintf("invocations%d", i), functionName, "Invocations", "Sum", errorRatePeriod),
+				lambdaMetricQuery(fmt.Sprintf("errors%d", i), functionName, "Errors", "Sum", errorRatePeriod))
+		}
+
+		results, err := fetchMetricData(queries, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, functionName := range chunk {
+			invocations := sum(results[fmt.Sprintf("invocations%d", i)].Values)
+			if invocations > 0 {
+				rates[functionName] = sum(results[fmt.Sprintf("errors%d", i)].Values) / invocations * 100
+			}
+		}
+	}
+
+	return rates, nil
+}
+
+func lambdaMetricQuery(id, functionName, metricName, stat string, period time.Duration) cloudwatchtypes.MetricDataQuery {
+	return cloudwatchtypes.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &cloudwatchtypes.MetricStat{
+			Metric: &cloudwatchtypes.Metric{
+				Namespace:  aws.String("AWS/Lambda"),
+				MetricName: aws.String(metricName),
+				Dimensions: []cloudwatchtypes.Dimension{{Name: aws.String("FunctionName"), Value: aws.String(functionName)}},
+			},
+			Period: aws.Int32(int32(period.Seconds())),
+			Stat:   aws.String(stat),
+		},
+	}
+}
+
+// metricPeriod returns the period giving 60 values in the window. CloudWatch needs a multiple of a minute
+func metricPeriod(window time.Duration) time.Duration {
+	return max(window/60, time.Minute).Truncate(time.Minute)
+}
+
+// fetchMetricData runs the queries and returns the results by query id, with the values of all pages
+func fetchMetricData(queries []cloudwatchtypes.MetricDataQuery, start, end time.Time) (map[string]cloudwatchtypes.MetricDataResult, error) {
+	results := map[string]cloudwatchtypes.MetricDataResult{}
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(cloudwatchClient, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         aws.Time(start),
+		EndTime:           aws.Time(end),
+		ScanBy:            cloudwatchtypes.ScanByTimestampAscending,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range output.MetricDataResults {
+			id := aws.ToString(result.Id)
+			merged, found := results[id]
+			if !found {
+				results[id] = result
+				continue
+			}
+			merged.Timestamps = append(merged.Timestamps, result.Timestamps...)
+			merged.Values = append(merged.Values, result.Values...)
+			results[id] = merged
+		}
+	}
+
+	return results, nil
+}
+
+// newMetricSeries returns a series with a value for every period from start to end, periods missing in the
+// result are 0
+func newMetricSeries(label, stat string, result cloudwatchtypes.MetricDataResult, start, end time.Time, period time.Duration) MetricSeries {
+	values := map[int64]float64{}
+	for i, timestamp := range result.Timestamps {
+		if i < len(result.Values) {
+			values[timestamp.Unix()] = result.Values[i]
+		}
+	}
+
+	series := MetricSeries{Label: label, Stat: stat}
+	for when := start; when.Before(end); when = when.Add(period) {
+		series.Timestamps = append(series.Timestamps, when)
+		series.Values = append(series.Values, values[when.Unix()])
+	}
+
+	return series
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+// FetchTailLogsChannel starts a live tail of the log group, optionally only with events matching a CloudWatch
+// filter pattern
+func FetchTailLogsChannel(logGroupArn, filterPattern string) (*cloudwatchlogs.StartLiveTailEventStream, error) {
+	input := &cloudwatchlogs.StartLiveTailInput{
+		LogGroupIdentifiers: []string{logGroupArn},
+	}
+	if filterPattern != "" {
+		input.LogEventFilterPattern = aws.String(filterPattern)
+	}
+
+	output, err := cloudwatchLogsClient.TailLogs(context.Background(), input)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to start log tail from cloudwatch")
 		return nil, err
@@ -195,6 +440,73 @@ func FetchTailLogsChannel(logGroupArn string) (*cloudwatchlogs.StartLiveTailEven
 	return output, nil
 }
 
+// maxLogEventPages is the number of pages FilterLogEvents reads at most
+const maxLogEventPages = 50
+
+// FilterLogEvents reads the log events of a log group (name or arn) between start and end, optionally matching a
+// CloudWatch filter pattern. If there are more than limit events, the most recent are returned. The events are read
+// oldest first and reading stops after maxLogEventPages pages, truncated is then set and the newest events of the
+// range are missing
+func FilterLogEvents(logGroupIdentifier string, start, end time.Time, pattern string, limit int) (events []awscloudwatchlogstypes.FilteredLogEvent, truncated bool, err error) {
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupIdentifier: aws.String(logGroupIdentifier),
+		StartTime:          aws.Int64(start.UnixMilli()),
+		EndTime:            aws.Int64(end.UnixMilli()),
+	}
+	if pattern != "" {
+		input.FilterPattern = aws.String(pattern)
+	}
+
+	events = make([]awscloudwatchlogstypes.FilteredLogEvent, 0)
+	for page := 0; ; page++ {
+		if page == maxLogEventPages {
+			log.Info().Msgf("Stopped reading events of %s after %d pages", logGroupIdentifier, maxLogEventPages)
+			return events, true, nil
+		}
+
+		output, err := cloudwatchLogsClient.FilterLogEvents(context.Background(), input)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to filter log events in %s", logGroupIdentifier)
+			return nil, false, err
+		}
+
+		events = append(events, output.Events...)
+		if len(events) > limit {
+			events = events[len(events)-limit:]
+		}
+
+		if output.NextToken == nil || *output.NextToken == "" {
+			return events, false, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// ValidateFilterPattern checks a CloudWatch filter pattern by filtering the last second of the log group (name or arn)
+// with it, CloudWatch rejects an invalid pattern
+func ValidateFilterPattern(logGroupIdentifier, pattern string) error {
+	end := time.Now()
+
+	_, err := cloudwatchLogsClient.FilterLogEvents(context.Background(), &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupIdentifier: aws.String(logGroupIdentifier),
+		FilterPattern:      aws.String(pattern),
+		StartTime:          aws.Int64(end.Add(-time.Second).UnixMilli()),
+		EndTime:            aws.Int64(end.UnixMilli()),
+		Limit:              aws.Int32(1),
+	})
+	return err
+}
+
+// ErrorMessage returns the message of an error returned by AWS without the operation and request details, or the
+// error as it is if it is not from AWS
+func ErrorMessage(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorMessage() != "" {
+		return apiErr.ErrorMessage()
+	}
+	return err.Error()
+}
+
 // FetchLogStreams fetches log streams for a given log group. If container and taskArn is provided, it is used to filter the returned result.
 func FetchLogStreams(logGroupName string, container, taskArn *string) ([]awscloudwatchlogstypes.LogStream, error) {
 	task := new(string)
@@ -257,16 +569,57 @@ func FetchPackagesFromECR(name string) ([]Package, error) {
 	return packages, nil
 }
 
-func UpdateECSImage(version, serviceName, clusterName string) error {
+// UpdateECSImage deploys the given image to the service and stores the image tag in the given SSM parameter
+func UpdateECSImage(version, serviceName, clusterName, imageTagParameter string) error {
 	_, err := awsecs.UpdateEcsService(context.TODO(), ecsClient, version, serviceName, clusterName)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to update ecs service")
 		return err
 	}
 
-	value := utils.RemoveAllBeforeLastChar(":", &version)
+	return PutImageTag(version, imageTagParameter)
+}
+
+// RollbackECSService points the service at a previous revision of its task definition. The image tag parameter is
+// not changed, see PutImageTag
+func RollbackECSService(serviceName, clusterName string, taskDefinition types.TaskDefinition) error {
+	_, err := ecsClient.UpdateService(context.TODO(), &ecs.UpdateServiceInput{
+		Cluster:        aws.String(clusterName),
+		Service:        aws.String(serviceName),
+		TaskDefinition: taskDefinition.TaskDefinitionArn,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to roll back ecs service")
+	}
+
+	return err
+}
+
+// ServiceImage returns the image of the service container in the task definition. Like UpdateECSImage, the container
+// with an image containing the service name is used, or the first container if there is none
+func ServiceImage(taskDefinition types.TaskDefinition, serviceName string) string {
+	if len(taskDefinition.ContainerDefinitions) == 0 {
+		return ""
+	}
+
+	for _, v := range taskDefinition.ContainerDefinitions {
+		if strings.Contains(aws.ToString(v.Image), serviceName) {
+			return aws.ToString(v.Image)
+		}
+	}
+
+	return aws.ToString(taskDefinition.ContainerDefinitions[0].Image)
+}
 
-	err = awsssm.UpdateParameterStoreParameter(context.TODO(), ssmClient, fmt.Sprintf("/%s/ecs/%s/image-tag", clusterName, serviceName), value)
+// PutImageTag stores the tag of the image in the SSM parameter
+func PutImageTag(image, imageTagParameter string) error {
+	value := utils.RemoveAllBeforeLastChar(":", &image)
+
+	_, err := ssmClient.PutParameter(context.TODO(), &ssm.PutParameterInput{
+		Name:      aws.String(imageTagParameter),
+		Value:     aws.String(value),
+		Overwrite: aws.Bool(true),
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to update ssm parameter")
 	}
@@ -281,13 +634,19 @@ func GetAccountInformation() (*string, *string, error) {
 		return nil, nil, err
 	}
 
-	// Load the AWS configuration
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	region := currentRegion
+
+	return output.Account, &region, nil
+}
+
+// GetCallerArn returns the ARN of the logged in user or role
+func GetCallerArn() (string, error) {
+	output, err := stsClient.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
 	if err != nil {
-		return nil, nil, fmt.Errorf("Unable to load SDK config, %v", err)
+		return "", err
 	}
 
-	return output.Account, &cfg.Region, nil
+	return aws.ToString(output.Arn), nil
 }
 
 // RestartECSService restarts an ECS service by using updateing the service and setting the
@@ -297,10 +656,10 @@ func RestartECSService(clusterName, name string) error {
 	return awsecs.StopEcsService(context.Background(), ecsClient, name, clusterName)
 }
 
-// FetchAvailableVersions reads all files in the s3 bucket and returns the name and created timestamp.
-// The Message field is not filled
-func FetchAvailableVersions(functionName string) ([]Version, error) {
-	files, err := awss3.ListBucketObjects(context.Background(), s3Client, s3_bucket_name, functionName)
+// FetchAvailableVersions reads all files with the given prefix in the s3 bucket and returns the key and created
+// timestamp. The Message field is not filled
+func FetchAvailableVersions(bucket, prefix string) ([]Version, error) {
+	files, err := awss3.ListBucketObjects(context.Background(), s3Client, bucket, prefix)
 	if err != nil {
 		return nil, err
 	}
@@ -341,20 +700,121 @@ func UpdateLambdaFunctionDescription(functionName, newDescription string) error
 	return awslambda.UpdateFunctionDescription(context.Background(), lambdaClient, functionName, newDescription)
 }
 
+// RestartLambdaFunction makes lambda start new execution environments for a function, by adding or removing
+// a trailing dot in the function description
+func RestartLambdaFunction(functionName string) error {
+	currentDescription, err := GetFunctionDescription(functionName)
+	if err != nil {
+		return err
+	}
+
+	return UpdateLambdaFunctionDescription(functionName, toggleTrailingDot(currentDescription))
+}
+
+func toggleTrailingDot(currentDescription *string) string {
+	if currentDescription == nil {
+		return "."
+	}
+
+	if strings.HasSuffix(*currentDescription, ".") {
+		return strings.TrimSuffix(*currentDescription, ".")
+	}
+	return fmt.Sprintf("%s.", *currentDescription)
+}
+
 // DeployLambdaFunction lets you update the function code for a specified lambda function by
 // setting the code to execute point to a zip file in a lambda bucket. Arch must match the architecture the
 // code is compiled for
-func DeployLambdaFunction(functionName, zipfile string, arch lambdatypes.Architecture) (*string, error) {
-	output, err := awslambda.UpdateFunctionCode(context.Background(), lambdaClient, functionName, s3_bucket_name, zipfile, arch)
+func DeployLambdaFunction(functionName, bucket, key string, arch lambdatypes.Architecture) (*string, error) {
+	log.Info().Msgf("Updating lambda function %s from bucket %s and file %s", functionName, bucket, key)
+
+	output, err := lambdaClient.UpdateFunctionCode(context.Background(), &lambda.UpdateFunctionCodeInput{
+		FunctionName:  aws.String(functionName),
+		S3Bucket:      aws.String(bucket),
+		S3Key:         aws.String(key),
+		Architectures: []lambdatypes.Architecture{arch},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.FunctionArn, nil
+}
+
+// DeployLambdaImage updates a lambda function with package type Image to run the given container image
+func DeployLambdaImage(functionName, imageUri string) (*string, error) {
+	log.Info().Msgf("Updating lambda function %s to image %s", functionName, imageUri)
+
+	output, err := lambdaClient.UpdateFunctionCode(context.Background(), &lambda.UpdateFunctionCodeInput{
+		FunctionName: aws.String(functionName),
+		ImageUri:     aws.String(imageUri),
+	})
 	if err != nil {
 		return nil, err
 	}
 	return output.FunctionArn, nil
 }
 
-// TagLambdaFunctionWithVersion adds LastDeployed tag to a lambda function
+// TagLambdaFunctionWithVersion tags a lambda function with the deployed version, the ARN of the caller and the time
 func TagLambdaFunctionWithVersion(functionName, version string) error {
-	return awslambda.TagLambdaFunction(context.Background(), lambdaClient, functionName, map[string]string{"LastDeployed": version})
+	tags := map[string]string{
+		LastDeployedTag:   version,
+		LastDeployedAtTag: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if arn, err := GetCallerArn(); err == nil {
+		tags[LastDeployedByTag] = arn
+	} else {
+		log.Error().Err(err).Msg("Failed to read caller identity")
+	}
+
+	return awslambda.TagLambdaFunction(context.Background(), lambdaClient, functionName, tags)
+}
+
+// FetchParameterHistory returns the values an SSM parameter has had, with the time and ARN of the user that set them,
+// newest first. A missing parameter has no history
+func FetchParameterHistory(name string) ([]LogItem, error) {
+	items := make([]LogItem, 0)
+
+	paginator := ssm.NewGetParameterHistoryPaginator(ssmClient, &ssm.GetParameterHistoryInput{
+		Name: aws.String(name),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			var notFound *ssmtypes.ParameterNotFound
+			if errors.As(err, &notFound) {
+				return items, nil
+			}
+			log.Error().Err(err).Msgf("Failed to read history of ssm parameter %s", name)
+			return nil, err
+		}
+
+		for _, v := range output.Parameters {
+			items = append(items, LogItem{
+				Updated: aws.ToTime(v.LastModifiedDate),
+				User:    aws.ToString(v.LastModifiedUser),
+				Version: aws.ToString(v.Value),
+			})
+		}
+	}
+
+	slices.Reverse(items)
+
+	return items, nil
+}
+
+// TagECSResource adds the tags to an ECS resource, e.g. a service
+func TagECSResource(arn string, tags map[string]string) error {
+	return awsecs.TagResource(context.Background(), ecsClient, arn, tags)
+}
+
+// UntagECSResource removes the tags with the given keys from an ECS resource
+func UntagECSResource(arn string, keys ...string) error {
+	_, err := ecsClient.UntagResource(context.Background(), &ecs.UntagResourceInput{
+		ResourceArn: aws.String(arn),
+		TagKeys:     keys,
+	})
+	return err
 }
 
 // ListECSClusters returns a slice of all ECS clusters found in the account
@@ -364,6 +824,10 @@ func ListECSClusters() ([]types.Cluster, error) {
 		return nil, err
 	}
 
+	if len(list.ClusterArns) == 0 {
+		return []types.Cluster{}, nil
+	}
+
 	// If STATISTICS is specified, the task and service count is included, separated by launch type.
 	output, err := awsecs.DescribeClusters(context.Background(), ecsClient, list.ClusterArns, []types.ClusterField{types.ClusterFieldStatistics})
 	if err != nil {
@@ -403,6 +867,21 @@ func DescribeClusterServices(clusterName string) ([]types.Service, error) {
 	return services, err
 }
 
+// DescribeService returns the description of a single service in the given ECS cluster, including its
+// deployments and events
+func DescribeService(clusterName, serviceName string) (*types.Service, error) {
+	output, err := awsecs.DescribeEcsService(context.Background(), ecsClient, serviceName, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.Services) == 0 {
+		return nil, fmt.Errorf("service %s not found in cluster %s", serviceName, clusterName)
+	}
+
+	return &output.Services[0], nil
+}
+
 // DescribeClusterTasks lists all tasks in a cluster or service (if serivceName != nil) and
 // returns a slice of the tasks found
 func DescribeClusterTasks(clusterName, serviceName *string) ([]types.Task, error) {
@@ -451,7 +930,357 @@ func GetTaskDefinitions(taskDefinitionArns []string) ([]types.TaskDefinition, er
 	return taskDefinitions, nil
 }
 
+// ScaleECSService sets the desired number of tasks of the service
+func ScaleECSService(clusterName, serviceName string, desiredCount int32) error {
+	_, err := ecsClient.UpdateService(context.TODO(), &ecs.UpdateServiceInput{
+		Cluster:      aws.String(clusterName),
+		Service:      aws.String(serviceName),
+		DesiredCount: aws.Int32(desiredCount),
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to scale ecs service %s", serviceName)
+	}
+	return err
+}
+
+// FetchServiceScalingLimits returns the minimum and maximum capacity registered for the service in Application Auto
+// Scaling, or nil if the service is not a scalable target
+func FetchServiceScalingLimits(clusterName, serviceName string) (*ScalingLimits, error) {
+	output, err := autoScalingClient.DescribeScalableTargets(context.Background(), &applicationautoscaling.DescribeScalableTargetsInput{
+		ServiceNamespace:  autoscalingtypes.ServiceNamespaceEcs,
+		ScalableDimension: autoscalingtypes.ScalableDimensionECSServiceDesiredCount,
+		ResourceIds:       []string{fmt.Sprintf("service/%s/%s", clusterName, serviceName)},
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read scalable targets for ecs service %s", serviceName)
+		return nil, err
+	}
+
+	if len(output.ScalableTargets) == 0 {
+		return nil, nil
+	}
+
+	target := output.ScalableTargets[0]
+
+	return &ScalingLimits{
+		Min: aws.ToInt32(target.MinCapacity),
+		Max: aws.ToInt32(target.MaxCapacity),
+	}, nil
+}
+
+// SetServiceMinCapacity changes the minimum capacity of a service registered in Application Auto Scaling, the
+// maximum capacity is kept
+func SetServiceMinCapacity(clusterName, serviceName string, minCapacity int32) error {
+	_, err := autoScalingClient.RegisterScalableTarget(context.Background(), &applicationautoscaling.RegisterScalableTargetInput{
+		ServiceNamespace:  autoscalingtypes.ServiceNamespaceEcs,
+		ScalableDimension: autoscalingtypes.ScalableDimensionECSServiceDesiredCount,
+		ResourceId:        aws.String(fmt.Sprintf("service/%s/%s", clusterName, serviceName)),
+		MinCapacity:       aws.Int32(minCapacity),
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to set the minimum capacity of ecs service %s", serviceName)
+	}
+	return err
+}
+
+// ListTaskDefinitionRevisions returns up to max active revisions of the task definition family, newest first
+func ListTaskDefinitionRevisions(family string, max int32) ([]types.TaskDefinition, error) {
+	output, err := ecsClient.ListTaskDefinitions(context.Background(), &ecs.ListTaskDefinitionsInput{
+		FamilyPrefix: aws.String(family),
+		Status:       types.TaskDefinitionStatusActive,
+		Sort:         types.SortOrderDesc,
+		MaxResults:   aws.Int32(max),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetTaskDefinitions(output.TaskDefinitionArns)
+}
+
 // ShortTaskDefArn returns a short version of the task definition arn
 func ShortenTaskDefArn(taskDefinitionArn *string) string {
 	return utils.RemoveAllBeforeLastChar("/", taskDefinitionArn)
 }
+
+// LatestVersion is the unpublished version of a lambda function, the one updated when code is deployed
+const LatestVersion = "$LATEST"
+
+// lambdaUpdateTimeout is how long to wait for a lambda function update to complete
+const lambdaUpdateTimeout = 2 * time.Minute
+
+// WaitForLambdaUpdate waits until the last update of a lambda function has completed. Code and configuration
+// updates are applied asynchronously, and a function cannot be published or updated again until they are done
+func WaitForLambdaUpdate(functionName string) error {
+	waiter := lambda.NewFunctionUpdatedV2Waiter(lambdaClient)
+	return waiter.Wait(context.Background(), &lambda.GetFunctionInput{FunctionName: aws.String(functionName)}, lambdaUpdateTimeout)
+}
+
+// ListLambdaVersions returns the versions of a lambda function, $LATEST first followed by the published versions,
+// newest first
+func ListLambdaVersions(functionName string) ([]lambdatypes.FunctionConfiguration, error) {
+	versions := make([]lambdatypes.FunctionConfiguration, 0)
+
+	paginator := lambda.NewListVersionsByFunctionPaginator(lambdaClient, &lambda.ListVersionsByFunctionInput{
+		FunctionName: aws.String(functionName),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, output.Versions...)
+	}
+
+	slices.SortStableFunc(versions, func(a, b lambdatypes.FunctionConfiguration) int {
+		return versionNumber(b.Version) - versionNumber(a.Version)
+	})
+
+	return versions, nil
+}
+
+// versionNumber returns the number of a published version, $LATEST sorts above every published version
+func versionNumber(version *string) int {
+	if aws.ToString(version) == LatestVersion {
+		return math.MaxInt32
+	}
+	number, _ := strconv.Atoi(aws.ToString(version))
+	return number
+}
+
+// ListLambdaAliases returns the aliases of a lambda function
+func ListLambdaAliases(functionName string) ([]lambdatypes.AliasConfiguration, error) {
+	aliases := make([]lambdatypes.AliasConfiguration, 0)
+
+	paginator := lambda.NewListAliasesPaginator(lambdaClient, &lambda.ListAliasesInput{
+		FunctionName: aws.String(functionName),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, output.Aliases...)
+	}
+
+	return aliases, nil
+}
+
+// PublishLambdaVersion publishes the current code and configuration of a lambda function as a new version and
+// returns the version number. A pending update of the function is waited for first
+func PublishLambdaVersion(functionName, description string) (string, error) {
+	if err := WaitForLambdaUpdate(functionName); err != nil {
+		return "", fmt.Errorf("function %s was not ready to publish: %w", functionName, err)
+	}
+
+	output, err := lambdaClient.PublishVersion(context.Background(), &lambda.PublishVersionInput{
+		FunctionName: aws.String(functionName),
+		Description:  aws.String(description),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.Version), nil
+}
+
+// UpdateLambdaAlias points an alias at a version. Weights route a share of the traffic, between 0 and 1, to
+// additional versions, an empty map sends all traffic to the version
+func UpdateLambdaAlias(functionName, aliasName, version string, weights map[string]float64) error {
+	log.Info().Msgf("Pointing alias %s of %s to version %s with additional weights %v", aliasName, functionName, version, weights)
+
+	_, err := lambdaClient.UpdateAlias(context.Background(), &lambda.UpdateAliasInput{
+		FunctionName:    aws.String(functionName),
+		Name:            aws.String(aliasName),
+		FunctionVersion: aws.String(version),
+		RoutingConfig: &lambdatypes.AliasRoutingConfiguration{
+			AdditionalVersionWeights: weights,
+		},
+	})
+
+	return err
+}
+
+// InvokeLambdaFunction invokes a lambda function with the payload. A synchronous invocation waits for the function
+// to return and includes the tail of the execution log, an asynchronous invocation only queues the event
+func InvokeLambdaFunction(functionName string, payload []byte, async bool) (*InvokeResult, error) {
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(functionName),
+		Payload:        payload,
+		InvocationType: lambdatypes.InvocationTypeRequestResponse,
+		LogType:        lambdatypes.LogTypeTail,
+	}
+	if async {
+		input.InvocationType = lambdatypes.InvocationTypeEvent
+		input.LogType = lambdatypes.LogTypeNone
+	}
+
+	output, err := lambdaClient.Invoke(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InvokeResult{
+		StatusCode:      output.StatusCode,
+		FunctionError:   aws.ToString(output.FunctionError),
+		ExecutedVersion: aws.ToString(output.ExecutedVersion),
+		Payload:         output.Payload,
+	}
+
+	if output.LogResult != nil {
+		decoded, err := base64.StdEncoding.DecodeString(*output.LogResult)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to decode log of %s", functionName)
+		}
+		result.Log = string(decoded)
+	}
+
+	return result, nil
+}
+
+// GetLambdaConfiguration reads the editable configuration of a lambda function
+func GetLambdaConfiguration(functionName string) (*LambdaConfiguration, error) {
+	output, err := lambdaClient.GetFunctionConfiguration(context.Background(), &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := &LambdaConfiguration{
+		Description: aws.ToString(output.Description),
+		MemorySize:  aws.ToInt32(output.MemorySize),
+		Timeout:     aws.ToInt32(output.Timeout),
+		Environment: map[string]string{},
+	}
+	if output.EphemeralStorage != nil {
+		configuration.EphemeralStorage = aws.ToInt32(output.EphemeralStorage.Size)
+	}
+	if output.Environment != nil {
+		// the variables are left out when lambda can not decrypt them, saving the configuration would remove them
+		if output.Environment.Error != nil {
+			return nil, fmt.Errorf("failed to read the environment variables of %s: %s: %s", functionName,
+				aws.ToString(output.Environment.Error.ErrorCode), aws.ToString(output.Environment.Error.Message))
+		}
+		if output.Environment.Variables != nil {
+			configuration.Environment = output.Environment.Variables
+		}
+	}
+
+	return configuration, nil
+}
+
+// UpdateLambdaConfiguration replaces the editable configuration of a lambda function and waits until the update
+// has completed. The environment variables are left as they are when Environment is nil, otherwise all variables
+// are replaced and variables missing in the configuration are removed
+func UpdateLambdaConfiguration(functionName string, configuration LambdaConfiguration) error {
+	log.Info().Msgf("Updating configuration of lambda function %s", functionName)
+
+	if err := WaitForLambdaUpdate(functionName); err != nil {
+		return fmt.Errorf("function %s was not ready to update: %w", functionName, err)
+	}
+
+	input := &lambda.UpdateFunctionConfigurationInput{
+		FunctionName:     aws.String(functionName),
+		Description:      aws.String(configuration.Description),
+		MemorySize:       aws.Int32(configuration.MemorySize),
+		Timeout:          aws.Int32(configuration.Timeout),
+		EphemeralStorage: &lambdatypes.EphemeralStorage{Size: aws.Int32(configuration.EphemeralStorage)},
+	}
+	if configuration.Environment != nil {
+		input.Environment = &lambdatypes.Environment{Variables: configuration.Environment}
+	}
+
+	_, err := lambdaClient.UpdateFunctionConfiguration(context.Background(), input)
+	if err != nil {
+		return err
+	}
+
+	if err := WaitForLambdaUpdate(functionName); err != nil {
+		// the reason of a failed update is in the configuration
+		if output, getErr := lambdaClient.GetFunctionConfiguration(context.Background(), &lambda.GetFunctionConfigurationInput{
+			FunctionName: aws.String(functionName),
+		}); getErr == nil && output.LastUpdateStatus == lambdatypes.LastUpdateStatusFailed {
+			return fmt.Errorf("update of %s failed: %s", functionName, aws.ToString(output.LastUpdateStatusReason))
+		}
+		return fmt.Errorf("update of %s did not complete: %w", functionName, err)
+	}
+
+	return nil
+}
+
+// TagLambdaFunction sets tags on a lambda function, identified by its arn
+func TagLambdaFunction(functionArn string, tags map[string]string) error {
+	return awslambda.TagLambdaFunction(context.Background(), lambdaClient, functionArn, tags)
+}
+
+// UntagLambdaFunction removes tags from a lambda function, identified by its arn
+func UntagLambdaFunction(functionArn string, keys ...string) error {
+	_, err := lambdaClient.UntagResource(context.Background(), &lambda.UntagResourceInput{
+		Resource: aws.String(functionArn),
+		TagKeys:  keys,
+	})
+	return err
+}
+
+// SetReservedConcurrency reserves concurrent executions for a lambda function. A reserved concurrency of 0
+// throttles every invocation of the function
+func SetReservedConcurrency(functionName string, concurrency int32) error {
+	log.Info().Msgf("Setting reserved concurrency of %s to %d", functionName, concurrency)
+
+	_, err := lambdaClient.PutFunctionConcurrency(context.Background(), &lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 aws.String(functionName),
+		ReservedConcurrentExecutions: aws.Int32(concurrency),
+	})
+	return err
+}
+
+// RemoveReservedConcurrency lets a lambda function use the unreserved concurrency of the account
+func RemoveReservedConcurrency(functionName string) error {
+	log.Info().Msgf("Removing reserved concurrency of %s", functionName)
+
+	_, err := lambdaClient.DeleteFunctionConcurrency(context.Background(), &lambda.DeleteFunctionConcurrencyInput{
+		FunctionName: aws.String(functionName),
+	})
+	return err
+}
+
+// ListProvisionedConcurrency returns the provisioned concurrency configured on the aliases and versions of a
+// lambda function
+func ListProvisionedConcurrency(functionName string) ([]lambdatypes.ProvisionedConcurrencyConfigListItem, error) {
+	configs := make([]lambdatypes.ProvisionedConcurrencyConfigListItem, 0)
+
+	paginator := lambda.NewListProvisionedConcurrencyConfigsPaginator(lambdaClient, &lambda.ListProvisionedConcurrencyConfigsInput{
+		FunctionName: aws.String(functionName),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, output.ProvisionedConcurrencyConfigs...)
+	}
+
+	return configs, nil
+}
+
+// SetProvisionedConcurrency configures provisioned concurrency on an alias or version of a lambda function. A
+// concurrency of 0 removes the configuration
+func SetProvisionedConcurrency(functionName, qualifier string, concurrency int32) error {
+	log.Info().Msgf("Setting provisioned concurrency of %s:%s to %d", functionName, qualifier, concurrency)
+
+	if concurrency == 0 {
+		_, err := lambdaClient.DeleteProvisionedConcurrencyConfig(context.Background(), &lambda.DeleteProvisionedConcurrencyConfigInput{
+			FunctionName: aws.String(functionName),
+			Qualifier:    aws.String(qualifier),
+		})
+		return err
+	}
+
+	_, err := lambdaClient.PutProvisionedConcurrencyConfig(context.Background(), &lambda.PutProvisionedConcurrencyConfigInput{
+		FunctionName:                    aws.String(functionName),
+		Qualifier:                       aws.String(qualifier),
+		ProvisionedConcurrentExecutions: aws.Int32(concurrency),
+	})
+	return err
+}
@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+func TestMetricPeriod(t *testing.T) {
+	tests := map[time.Duration]time.Duration{
+		time.Hour:        time.Minute,
+		6 * time.Hour:    6 * time.Minute,
+		24 * time.Hour:   24 * time.Minute,
+		time.Minute:      time.Minute,
+		90 * time.Minute: time.Minute,
+	}
+
+	for window, want := range tests {
+		if got := metricPeriod(window); got != want {
+			t.Errorf("metricPeriod(%s) = %s, want %s", window, got, want)
+		}
+	}
+}
+
+func TestNewMetricSeries(t *testing.T) {
+	start := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	end := start.Add(5 * time.Minute)
+
+	// the result is missing the periods without data, and has a timestamp without a value
+	result := cloudwatchtypes.MetricDataResult{
+		Timestamps: []time.Time{start.Add(time.Minute), start.Add(3 * time.Minute), start.Add(4 * time.Minute)},
+		Values:     []float64{4, 2},
+	}
+
+	series := newMetricSeries("Invocations", "Sum", result, start, end, time.Minute)
+
+	if series.Label != "Invocations" || series.Stat != "Sum" {
+		t.Errorf("got label %q and stat %q", series.Label, series.Stat)
+	}
+	if want := []float64{0, 4, 0, 2, 0}; !slices.Equal(series.Values, want) {
+		t.Errorf("got values %v, want %v", series.Values, want)
+	}
+	if len(series.Timestamps) != 5 || !series.Timestamps[0].Equal(start) || !series.Timestamps[4].Equal(start.Add(4*time.Minute)) {
+		t.Errorf("got timestamps %v, want one per minute from the start", series.Timestamps)
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	start := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Minute)
+
+	utilized := cloudwatchtypes.MetricDataResult{
+		Timestamps: []time.Time{start, start.Add(time.Minute), start.Add(2 * time.Minute)},
+		Values:     []float64{128, 256, 64},
+	}
+	// the reserved capacity is missing in the last period
+	reserved := cloudwatchtypes.MetricDataResult{
+		Timestamps: []time.Time{start, start.Add(time.Minute)},
+		Values:     []float64{512, 512},
+	}
+
+	series := utilization("Memory", utilized, reserved, start, end, time.Minute)
+
+	if series.Unit != "%" {
+		t.Errorf("got unit %q, want %%", series.Unit)
+	}
+	if want := []float64{25, 50, 0}; !slices.Equal(series.Values, want) {
+		t.Errorf("got values %v, want %v", series.Values, want)
+	}
+}
@@ -37,3 +37,46 @@ type Package struct {
 	RegistryID     string
 	RepositoryName string
 }
+
+// ScalingLimits is the capacity range registered for a scalable target in Application Auto Scaling
+type ScalingLimits struct {
+	Min int32
+	Max int32
+}
+
+// Allows returns true if the desired count is within the limits
+func (l *ScalingLimits) Allows(desiredCount int32) bool {
+	return desiredCount >= l.Min && desiredCount <= l.Max
+}
+
+// InvokeResult is the outcome of invoking a lambda function. Payload and Log are only set for synchronous
+// invocations, Log holds the last 4 KB of the execution log
+type InvokeResult struct {
+	StatusCode      int32
+	FunctionError   string
+	ExecutedVersion string
+	Payload         []byte
+	Log             string
+}
+
+// LambdaConfiguration is the part of the configuration of a lambda function that can be edited in s9k. Memory size
+// and ephemeral storage are in MB, the timeout in seconds
+type LambdaConfiguration struct {
+	Description      string
+	MemorySize       int32
+	Timeout          int32
+	EphemeralStorage int32
+	// Environment holds the environment variables, nil when an update should leave them as they are
+	Environment map[string]string
+}
+
+// MetricSeries is a CloudWatch metric read with one statistic, one value per period with the oldest value first.
+// Periods without data have the value 0
+type MetricSeries struct {
+	Label string
+	Stat  string
+	// Unit is the unit of the values, e.g. ms or %, empty for counts
+	Unit       string
+	Timestamps []time.Time
+	Values     []float64
+}
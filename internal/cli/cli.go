@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/bsek/s9k/internal/data"
+)
+
+// command is a non-interactive subcommand
+type command struct {
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"clusters":  {"clusters " + outputUsage, "List ECS clusters", clustersCommand},
+		"services":  {"services [-cluster <name>] " + outputUsage, "List the services in an ECS cluster", servicesCommand},
+		"functions": {"functions " + outputUsage, "List lambda functions", functionsCommand},
+		"apis":      {"apis " + outputUsage, "List api gateway apis", apisCommand},
+		"deploy":    {"deploy service [-cluster <name>] <service> <version|image> | deploy function <function> <version>", "Deploy a version of a service or lambda function", deployCommand},
+		"restart":   {"restart service [-cluster <name>] <service> | restart function <function>", "Restart a service or lambda function", restartCommand},
+		"park":      {"park [-cluster <name>] " + outputUsage, "Save the desired counts of the services in a cluster and scale them to 0", parkCommand},
+		"unpark":    {"unpark [-cluster <name>] " + outputUsage, "Scale the services in a parked cluster back to their saved desired counts", unparkCommand},
+		"logs":      {"logs [-follow] [-since <duration>] [-filter <pattern>] [-limit <n>] <log group>", "Print log events from a log group", logsCommand},
+	}
+}
+
+// errUsage is returned when a command is called with the wrong arguments, the usage is printed by Run
+var errUsage = errors.New("invalid arguments")
+
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// defaultCluster is the cluster given with the global -cluster flag
+var defaultCluster string
+
+// IsCommand returns true if name is a known subcommand
+func IsCommand(name string) bool {
+	_, found := commands[name]
+	return found
+}
+
+// Run executes the subcommand in args[0] with the remaining arguments. clusterName is the cluster given with the
+// global -cluster flag, used when a command needs a cluster and none is given
+func Run(args []string, clusterName string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	defaultCluster = clusterName
+
+	cmd, found := commands[args[0]]
+	if !found {
+		return fmt.Errorf("unknown command %s", args[0])
+	}
+
+	err := cmd.run(args[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(stderr, "Usage: s9k %s\n", cmd.usage)
+	}
+
+	return err
+}
+
+// PrintCommands writes the list of subcommands, used in the usage text of the application
+func PrintCommands(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, commands[name].description)
+	}
+	tw.Flush()
+}
+
+// parseFlags parses flags placed anywhere among the arguments and returns the positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	flags.SetOutput(io.Discard)
+
+	positional := make([]string, 0)
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveCluster returns the given cluster name, or the global cluster, or the first cluster in the account
+func resolveCluster(clusterName string) (string, error) {
+	if clusterName != "" {
+		return clusterName, nil
+	}
+	if defaultCluster != "" {
+		return defaultCluster, nil
+	}
+
+	clusters, err := data.LoadECSClusters()
+	if err != nil {
+		return "", err
+	}
+	if len(clusters) == 0 {
+		return "", errors.New("no ECS clusters found")
+	}
+
+	return *clusters[0].ClusterName, nil
+}
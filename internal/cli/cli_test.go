@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bsek/s9k/internal/artifacts"
+)
+
+func TestParseFlags(t *testing.T) {
+	flags := flag.NewFlagSet("deploy service", flag.ContinueOnError)
+	cluster := flags.String("cluster", "", "")
+
+	positional, err := parseFlags(flags, []string{"api", "-cluster", "prod", "sha-4f1c2ab"})
+	if err != nil {
+		t.Fatalf("parseFlags() failed: %v", err)
+	}
+
+	if *cluster != "prod" {
+		t.Errorf("got cluster %q, want prod", *cluster)
+	}
+	if want := []string{"api", "sha-4f1c2ab"}; !slices.Equal(positional, want) {
+		t.Errorf("got arguments %v, want %v", positional, want)
+	}
+}
+
+func TestParseUnknownFlag(t *testing.T) {
+	flags := flag.NewFlagSet("restart service", flag.ContinueOnError)
+
+	if _, err := parseFlags(flags, []string{"api", "-force"}); !errors.Is(err, errUsage) {
+		t.Errorf("got error %v, want the usage error", err)
+	}
+}
+
+func TestRunPrintsUsage(t *testing.T) {
+	var out strings.Builder
+	previous := stderr
+	stderr = &out
+	t.Cleanup(func() { stderr = previous })
+
+	if err := Run([]string{"deploy", "service"}, ""); !errors.Is(err, errUsage) {
+		t.Errorf("got error %v, want the usage error", err)
+	}
+	if !strings.HasPrefix(out.String(), "Usage: s9k deploy service") {
+		t.Errorf("got %q, want the usage of deploy", out.String())
+	}
+
+	if err := Run([]string{"scale"}, ""); err == nil || errors.Is(err, errUsage) {
+		t.Errorf("got error %v, want unknown command", err)
+	}
+}
+
+// staticSource is an artifact source with a fixed list of artifacts
+type staticSource []artifacts.Artifact
+
+func (staticSource) Name() string {
+	return "static"
+}
+
+func (s staticSource) List() ([]artifacts.Artifact, error) {
+	return s, nil
+}
+
+func TestFindArtifact(t *testing.T) {
+	source := func() (artifacts.Source, error) {
+		return staticSource{
+			{Version: "v1.1.0.zip", Key: "email-sender/v1.1.0.zip"},
+			{Version: "v1.0.0.zip", Key: "email-sender/v1.0.0.zip"},
+		}, nil
+	}
+
+	for _, version := range []string{"v1.0.0.zip", "email-sender/v1.0.0.zip"} {
+		artifact, err := findArtifact(version, source)
+		if err != nil {
+			t.Errorf("findArtifact(%s) failed: %v", version, err)
+			continue
+		}
+		if artifact.Version != "v1.0.0.zip" {
+			t.Errorf("findArtifact(%s) = %s, want v1.0.0.zip", version, artifact.Version)
+		}
+	}
+
+	if _, err := findArtifact("v2.0.0.zip", source); err == nil || !strings.Contains(err.Error(), "not found in static") {
+		t.Errorf("got error %v, want version not found", err)
+	}
+}
+
+func TestFindArtifactWithoutSource(t *testing.T) {
+	source := func() (artifacts.Source, error) {
+		return nil, errors.New("no s3 bucket configured")
+	}
+
+	if _, err := findArtifact("v1.0.0.zip", source); err == nil {
+		t.Error("findArtifact() without a source succeeded")
+	}
+}
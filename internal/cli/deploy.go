@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/bsek/s9k/internal/artifacts"
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/config"
+)
+
+func deployCommand(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "service":
+		return deployService(args[1:])
+	case "function":
+		return deployFunction(args[1:])
+	}
+
+	return errUsage
+}
+
+func deployService(args []string) error {
+	flags := flag.NewFlagSet("deploy service", flag.ContinueOnError)
+	clusterFlag := flags.String("cluster", "", "")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errUsage
+	}
+
+	serviceName, version := positional[0], positional[1]
+
+	clusterName, err := resolveCluster(*clusterFlag)
+	if err != nil {
+		return err
+	}
+
+	// a full image reference is deployed as is, otherwise the version is looked up in the artifact source
+	image := version
+	if !strings.Contains(version, "/") {
+		artifact, err := findArtifact(version, func() (artifacts.Source, error) {
+			return artifacts.ForService(clusterName, serviceName)
+		})
+		if err != nil {
+			return err
+		}
+		image = artifact.Image
+	}
+
+	err = aws.UpdateECSImage(image, serviceName, clusterName, config.ForService(clusterName, serviceName).ImageTagParameterName())
+	if err != nil {
+		return fmt.Errorf("failed to deploy %s to service %s: %w", image, serviceName, err)
+	}
+
+	fmt.Fprintf(stdout, "Deployed %s to service %s in cluster %s\n", image, serviceName, clusterName)
+
+	return nil
+}
+
+func deployFunction(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	functionName, version := args[0], args[1]
+
+	artifact, err := findArtifact(version, func() (artifacts.Source, error) {
+		return artifacts.ForFunction(functionName)
+	})
+	if err != nil {
+		return err
+	}
+
+	arn, err := deployFunctionArtifact(functionName, artifact)
+	if err != nil {
+		return fmt.Errorf("failed to update %s to version %s: %w", functionName, artifact.Version, err)
+	}
+
+	if err := aws.TagLambdaFunctionWithVersion(*arn, artifact.Version); err != nil {
+		fmt.Fprintf(stderr, "Failed to tag %s with version %s: %v\n", functionName, artifact.Version, err)
+	}
+
+	fmt.Fprintf(stdout, "%s successfully updated to version %s\n", functionName, artifact.Version)
+
+	return nil
+}
+
+// deployFunctionArtifact updates the code of a lambda function to a container image or a zip file in S3, using
+// the current architecture of the function for zip files
+func deployFunctionArtifact(functionName string, artifact *artifacts.Artifact) (*string, error) {
+	if artifact.Image != "" {
+		return aws.DeployLambdaImage(functionName, artifact.Image)
+	}
+
+	function, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	arch := lambdatypes.ArchitectureX8664
+	if len(function.Configuration.Architectures) > 0 {
+		arch = function.Configuration.Architectures[0]
+	}
+
+	return aws.DeployLambdaFunction(functionName, artifact.Bucket, artifact.Key, arch)
+}
+
+// findArtifact looks up the artifact with the given version (or S3 key) in the source
+func findArtifact(version string, source func() (artifacts.Source, error)) (*artifacts.Artifact, error) {
+	s, err := source()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read versions from %s: %w", s.Name(), err)
+	}
+
+	for i := range list {
+		if list[i].Version == version || list[i].Key == version {
+			return &list[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("version %s not found in %s", version, s.Name())
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/aws/fake"
+	"github.com/bsek/s9k/internal/config"
+)
+
+// useDemoBackend installs a fake backend with the demo fixtures and captures the output of the commands
+func useDemoBackend(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	fixtures := fake.DemoFixtures()
+	fake.NewBackend(fixtures).Install()
+
+	cfg := config.Default()
+	cfg.S3Bucket = fixtures.Bucket
+	previous := config.Get()
+	config.Set(cfg)
+
+	var out bytes.Buffer
+	previousStdout, previousStderr := stdout, stderr
+	stdout, stderr = &out, &out
+	t.Cleanup(func() {
+		config.Set(previous)
+		stdout, stderr = previousStdout, previousStderr
+	})
+
+	return &out
+}
+
+func TestDeployService(t *testing.T) {
+	out := useDemoBackend(t)
+
+	if err := Run([]string{"deploy", "service", "-cluster", "dev", "api", "sha-27cd9e1"}, ""); err != nil {
+		t.Fatalf("deploy failed: %v\n%s", err, out)
+	}
+
+	services, err := aws.DescribeClusterServices("dev")
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+	var taskDefinitionArn string
+	for _, service := range services {
+		if awssdk.ToString(service.ServiceName) == "api" {
+			taskDefinitionArn = awssdk.ToString(service.TaskDefinition)
+		}
+	}
+
+	taskDefinitions, err := aws.GetTaskDefinitions([]string{taskDefinitionArn})
+	if err != nil || len(taskDefinitions) != 1 {
+		t.Fatalf("failed to read the task definition: %v", err)
+	}
+	if image := awssdk.ToString(taskDefinitions[0].ContainerDefinitions[0].Image); !strings.HasSuffix(image, "/dev-api:sha-27cd9e1") {
+		t.Errorf("got image %s, want the deployed version", image)
+	}
+}
+
+func TestDeployServiceUnknownVersion(t *testing.T) {
+	useDemoBackend(t)
+
+	err := Run([]string{"deploy", "service", "-cluster", "dev", "api", "sha-0000000"}, "")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("got error %v, want version not found", err)
+	}
+}
+
+func TestDeployFunction(t *testing.T) {
+	out := useDemoBackend(t)
+
+	if err := Run([]string{"deploy", "function", "email-sender", "v1.0.0.zip"}, ""); err != nil {
+		t.Fatalf("deploy failed: %v\n%s", err, out)
+	}
+
+	function, err := aws.GetLambdaFunction("email-sender")
+	if err != nil {
+		t.Fatalf("failed to read the function: %v", err)
+	}
+	if got := function.Tags["LastDeployed"]; got != "v1.0.0.zip" {
+		t.Errorf("got LastDeployed tag %q, want v1.0.0.zip", got)
+	}
+}
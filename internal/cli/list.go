@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// parseListFlags parses the flags of a list command without positional arguments
+func parseListFlags(name string, args []string, extra func(flags *flag.FlagSet)) (outputOptions, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	output := addOutputFlags(flags)
+	if extra != nil {
+		extra(flags)
+	}
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return output, err
+	}
+	if len(positional) > 0 {
+		return output, errUsage
+	}
+
+	return output, nil
+}
+
+func clustersCommand(args []string) error {
+	output, err := parseListFlags("clusters", args, nil)
+	if err != nil {
+		return err
+	}
+
+	clusters, err := data.LoadECSClusters()
+	if err != nil {
+		return fmt.Errorf("failed to read ecs clusters: %w", err)
+	}
+
+	t := table{columns: []column{
+		{key: "name", header: "Name"},
+		{key: "status", header: "Status"},
+		{key: "services", header: "Services"},
+		{key: "runningTasks", header: "Running tasks"},
+		{key: "pendingTasks", header: "Pending tasks"},
+		{key: "containerInstances", header: "Container instances"},
+	}}
+	for _, c := range clusters {
+		t.rows = append(t.rows, []any{
+			*c.ClusterName,
+			awssdk.ToString(c.Status),
+			c.ActiveServicesCount,
+			c.RunningTasksCount,
+			c.PendingTasksCount,
+			c.RegisteredContainerInstancesCount,
+		})
+	}
+
+	return output.print(stdout, t)
+}
+
+func servicesCommand(args []string) error {
+	var clusterFlag *string
+	output, err := parseListFlags("services", args, func(flags *flag.FlagSet) {
+		clusterFlag = flags.String("cluster", "", "")
+	})
+	if err != nil {
+		return err
+	}
+
+	clusterName, err := resolveCluster(*clusterFlag)
+	if err != nil {
+		return err
+	}
+
+	clusterData, err := data.LoadECSClusterData(clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to read services in cluster %s: %w", clusterName, err)
+	}
+
+	t := table{columns: []column{
+		{key: "name", header: "Name"},
+		{key: "cluster", header: "Cluster"},
+		{key: "taskDefinition", header: "TaskDef"},
+		{key: "images", header: "Images"},
+		{key: "lastDeployed", header: "Last Deployed"},
+		{key: "deploymentStatus", header: "Deployment status"},
+		{key: "desired", header: "Desired"},
+		{key: "running", header: "Running"},
+		{key: "pending", header: "Pending"},
+	}}
+	for _, service := range clusterData.Services {
+		var lastDeployed *time.Time
+		deployStatus := ""
+		if len(service.Service.Deployments) > 0 {
+			lastDeployed = service.Service.Deployments[0].CreatedAt
+			for _, v := range service.Service.Deployments {
+				if *v.Status == "PRIMARY" {
+					deployStatus = string(v.RolloutState)
+				}
+			}
+		}
+
+		images := lo.Uniq(lo.Map(service.Containers, func(c data.Container, _ int) string {
+			return c.Image
+		}))
+
+		t.rows = append(t.rows, []any{
+			*service.Service.ServiceName,
+			clusterName,
+			utils.RemoveAllBeforeLastChar("/", service.Service.TaskDefinition),
+			images,
+			lastDeployed,
+			deployStatus,
+			service.Service.DesiredCount,
+			service.Service.RunningCount,
+			service.Service.PendingCount,
+		})
+	}
+
+	return output.print(stdout, t)
+}
+
+func functionsCommand(args []string) error {
+	output, err := parseListFlags("functions", args, nil)
+	if err != nil {
+		return err
+	}
+
+	functions, err := data.LoadFunctions()
+	if err != nil {
+		return fmt.Errorf("failed to read lambda functions: %w", err)
+	}
+
+	t := table{columns: []column{
+		{key: "name", header: "Name"},
+		{key: "runtime", header: "Runtime"},
+		{key: "packageType", header: "Package type"},
+		{key: "version", header: "Version"},
+		{key: "codeSize", header: "Code size", format: func(v any) string { return utils.FormatBytes(v.(int64)) }},
+		{key: "memorySize", header: "Memory size", format: func(v any) string { return fmt.Sprintf("%d MB", v) }},
+		{key: "timeout", header: "Timeout", format: func(v any) string { return fmt.Sprintf("%d s", v) }},
+		{key: "reservedConcurrency", header: "Reserved"},
+		{key: "provisionedConcurrency", header: "Provisioned"},
+		{key: "errorRate", header: "Error rate", format: func(v any) string {
+			if v == nil {
+				return ""
+			}
+			return fmt.Sprintf("%.1f%%", v)
+		}},
+		{key: "architecture", header: "Architecture"},
+		{key: "lastModified", header: "Last modified"},
+	}}
+	for _, function := range functions {
+		var lastModified *time.Time
+		if modified, err := time.Parse("2006-01-02T15:04:05.9Z0700", awssdk.ToString(function.LastModified)); err == nil {
+			lastModified = &modified
+		}
+
+		architecture := ""
+		if len(function.Architectures) > 0 {
+			architecture = string(function.Architectures[0])
+		}
+
+		// functions without reserved concurrency have an empty value, 0 means the function is throttled
+		var reserved any
+		if function.ReservedConcurrency != nil {
+			reserved = *function.ReservedConcurrency
+		}
+
+		// the error rate of the last hour is empty for functions that were not invoked
+		var errorRate any
+		if function.ErrorRate != nil {
+			errorRate = *function.ErrorRate
+		}
+
+		t.rows = append(t.rows, []any{
+			*function.FunctionName,
+			string(function.Runtime),
+			string(function.PackageType),
+			function.Tags[aws.LastDeployedTag],
+			function.CodeSize,
+			awssdk.ToInt32(function.MemorySize),
+			awssdk.ToInt32(function.Timeout),
+			reserved,
+			function.ProvisionedConcurrency,
+			errorRate,
+			architecture,
+			lastModified,
+		})
+	}
+
+	return output.print(stdout, t)
+}
+
+func apisCommand(args []string) error {
+	output, err := parseListFlags("apis", args, nil)
+	if err != nil {
+		return err
+	}
+
+	t := table{columns: []column{
+		{key: "name", header: "Name"},
+		{key: "id", header: "Id"},
+		{key: "domainName", header: "Domain name"},
+		{key: "protocol", header: "Protocol"},
+		{key: "description", header: "Description"},
+		{key: "created", header: "Created"},
+		{key: "logGroupArn", header: "Log group"},
+	}}
+	for _, api := range data.LoadApis() {
+		t.rows = append(t.rows, []any{
+			api.Name,
+			api.ApiId,
+			api.DomainName,
+			api.Type.String(),
+			api.Description,
+			api.CreatedDate,
+			api.LogGropuArn,
+		})
+	}
+
+	return output.print(stdout, t)
+}
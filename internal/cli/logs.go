@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/logs"
+)
+
+func logsCommand(args []string) error {
+	flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := flags.Bool("follow", false, "")
+	since := flags.Duration("since", 10*time.Minute, "")
+	filter := flags.String("filter", "", "")
+	limit := flags.Int("limit", 1000, "")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	logGroup := positional[0]
+
+	if *since > 0 {
+		end := time.Now()
+		events, truncated, err := aws.FilterLogEvents(logGroup, end.Add(-*since), end, *filter, *limit)
+		if err != nil {
+			return fmt.Errorf("failed to read log events from %s: %w", logGroup, err)
+		}
+
+		for _, event := range events {
+			printLogMessage(event.Message)
+		}
+
+		// the events are read oldest first, the newest are missing when reading stopped early
+		if truncated {
+			fmt.Fprintf(stderr, "Warning: too many events to read, events after %s are missing, use a shorter -since or a -filter\n",
+				lastEventTime(events))
+		}
+	}
+
+	if !*follow {
+		return nil
+	}
+
+	return followLogs(logGroup, *filter)
+}
+
+// followLogs prints events from a live tail of the log group until interrupted or the stream is closed
+func followLogs(logGroup, filter string) error {
+	logGroupArn := logGroup
+	if !strings.HasPrefix(logGroup, "arn:") {
+		arn, err := logs.ConstructLogGroupArn(logGroup)
+		if err != nil {
+			return err
+		}
+		logGroupArn = *arn
+	}
+
+	stream, err := aws.FetchTailLogsChannel(logGroupArn, filter)
+	if err != nil {
+		return fmt.Errorf("failed to start live tail of %s: %w", logGroup, err)
+	}
+	defer stream.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events := stream.Events()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, open := <-events:
+			if !open {
+				if err := stream.Err(); err != nil {
+					return fmt.Errorf("live tail of %s failed: %w", logGroup, err)
+				}
+				return errors.New("live tail session ended")
+			}
+
+			if update, ok := event.(*types.StartLiveTailResponseStreamMemberSessionUpdate); ok {
+				for _, logEvent := range update.Value.SessionResults {
+					printLogMessage(logEvent.Message)
+				}
+			}
+		}
+	}
+}
+
+// lastEventTime returns the time of the last event, or the start of the range if there are none
+func lastEventTime(events []types.FilteredLogEvent) string {
+	if len(events) == 0 {
+		return "the start of the range"
+	}
+	return time.UnixMilli(awssdk.ToInt64(events[len(events)-1].Timestamp)).Format(time.RFC3339)
+}
+
+func printLogMessage(message *string) {
+	if message != nil {
+		fmt.Fprintln(stdout, strings.TrimRight(*message, "\n"))
+	}
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// column describes a column in the result of a list command
+type column struct {
+	// key is used to select the column with -columns and as field name in json, yaml and csv output
+	key    string
+	header string
+	// format converts values to text for table output, values are formatted with formatValue if nil
+	format func(value any) string
+}
+
+// table is the result of a list command. Values keep their type, so json and yaml output contain numbers,
+// lists and timestamps
+type table struct {
+	columns []column
+	rows    [][]any
+}
+
+// outputOptions holds the output flags shared by the list commands
+type outputOptions struct {
+	format  *string
+	columns *string
+}
+
+// addOutputFlags adds -output (-o) and -columns to the flags of a list command
+func addOutputFlags(flags *flag.FlagSet) outputOptions {
+	options := outputOptions{
+		format:  flags.String("output", formatTable, ""),
+		columns: flags.String("columns", "", ""),
+	}
+	flags.StringVar(options.format, "o", formatTable, "")
+
+	return options
+}
+
+// outputUsage is appended to the usage of list commands
+const outputUsage = "[-output table|json|yaml|csv] [-columns <key,key,...>]"
+
+// print writes the table in the selected format with the selected columns
+func (o outputOptions) print(w io.Writer, t table) error {
+	t, err := selectColumns(t, *o.columns)
+	if err != nil {
+		return err
+	}
+
+	switch *o.format {
+	case formatTable, "":
+		return printTable(w, t)
+	case formatJSON:
+		return printJSON(w, t)
+	case formatYAML:
+		return printYAML(w, t)
+	case formatCSV:
+		return printCSV(w, t)
+	}
+
+	return fmt.Errorf("unknown output format %s, use one of %s, %s, %s or %s", *o.format, formatTable, formatJSON, formatYAML, formatCSV)
+}
+
+// selectColumns returns a table with the comma separated columns in the given order, or all columns if empty
+func selectColumns(t table, selection string) (table, error) {
+	if strings.TrimSpace(selection) == "" {
+		return t, nil
+	}
+
+	indexes := make([]int, 0)
+	for _, key := range strings.Split(selection, ",") {
+		key = strings.TrimSpace(key)
+		_, index, found := lo.FindIndexOf(t.columns, func(c column) bool {
+			return strings.EqualFold(c.key, key)
+		})
+		if !found {
+			keys := lo.Map(t.columns, func(c column, _ int) string { return c.key })
+			return t, fmt.Errorf("unknown column %s, available columns are: %s", key, strings.Join(keys, ","))
+		}
+		indexes = append(indexes, index)
+	}
+
+	selected := table{
+		columns: lo.Map(indexes, func(i int, _ int) column { return t.columns[i] }),
+		rows:    make([][]any, 0, len(t.rows)),
+	}
+	for _, row := range t.rows {
+		selected.rows = append(selected.rows, lo.Map(indexes, func(i int, _ int) any { return row[i] }))
+	}
+
+	return selected, nil
+}
+
+// printTable writes the table as aligned text columns
+func printTable(w io.Writer, t table) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	headers := lo.Map(t.columns, func(c column, _ int) string { return c.header })
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(t.formatRow(row), "\t"))
+	}
+
+	return tw.Flush()
+}
+
+func printCSV(w io.Writer, t table) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(lo.Map(t.columns, func(c column, _ int) string { return c.key })); err != nil {
+		return err
+	}
+
+	// csv values are written without the table formatting, e.g. sizes in bytes and timestamps in RFC3339
+	for _, row := range t.rows {
+		values := lo.Map(row, func(value any, _ int) string { return formatRaw(value) })
+		if err := cw.Write(values); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func printJSON(w io.Writer, t table) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(t.objects())
+}
+
+func printYAML(w io.Writer, t table) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(t.objects()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// formatRow converts the values of a row to text for table output
+func (t table) formatRow(row []any) []string {
+	values := make([]string, 0, len(row))
+	for i, value := range row {
+		if t.columns[i].format != nil {
+			values = append(values, t.columns[i].format(value))
+		} else {
+			values = append(values, formatValue(value))
+		}
+	}
+	return values
+}
+
+// objects returns the rows as objects with the column keys as field names, in column order
+func (t table) objects() []object {
+	objects := make([]object, 0, len(t.rows))
+	for _, row := range t.rows {
+		objects = append(objects, object{keys: lo.Map(t.columns, func(c column, _ int) string { return c.key }), values: row})
+	}
+	return objects
+}
+
+// object is a row that keeps the order of its fields when written as json or yaml
+type object struct {
+	keys   []string
+	values []any
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(k)
+		buffer.WriteByte(':')
+		buffer.Write(v)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+func (o object) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, key := range o.keys {
+		var value yaml.Node
+		if err := value.Encode(o.values[i]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &value)
+	}
+	return node, nil
+}
+
+// formatValue converts a value to text for table output
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *time.Time:
+		if v == nil {
+			return "n/a"
+		}
+		return utils.FormatLocalDateTime(*v)
+	case time.Time:
+		if v.IsZero() {
+			return "n/a"
+		}
+		return utils.FormatLocalDateTime(v)
+	case []string:
+		return strings.Join(v, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// formatRaw converts a value to text for csv output
+func formatRaw(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, " ")
+	}
+	return fmt.Sprint(value)
+}
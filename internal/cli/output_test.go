@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTable() table {
+	deployed := time.Date(2024, 5, 17, 14, 30, 0, 0, time.UTC)
+
+	return table{
+		columns: []column{
+			{key: "name", header: "Name"},
+			{key: "running", header: "Running"},
+			{key: "images", header: "Images"},
+			{key: "deployed", header: "Deployed"},
+		},
+		rows: [][]any{
+			{"api", int32(2), []string{"api:sha-1", "proxy:1.25"}, &deployed},
+			{"worker", int32(0), []string{}, (*time.Time)(nil)},
+		},
+	}
+}
+
+func printWith(t *testing.T, format, columns string) string {
+	t.Helper()
+
+	options := outputOptions{format: &format, columns: &columns}
+
+	var out bytes.Buffer
+	if err := options.print(&out, testTable()); err != nil {
+		t.Fatalf("print as %s failed: %v", format, err)
+	}
+	return out.String()
+}
+
+func TestPrintJSON(t *testing.T) {
+	got := printWith(t, formatJSON, "")
+
+	want := `[
+  {
+    "name": "api",
+    "running": 2,
+    "images": [
+      "api:sha-1",
+      "proxy:1.25"
+    ],
+    "deployed": "2024-05-17T14:30:00Z"
+  },
+  {
+    "name": "worker",
+    "running": 0,
+    "images": [],
+    "deployed": null
+  }
+]
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrintYAML(t *testing.T) {
+	got := printWith(t, formatYAML, "name,running")
+
+	want := `- name: api
+  running: 2
+- name: worker
+  running: 0
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	got := printWith(t, formatCSV, "")
+
+	want := `name,running,images,deployed
+api,2,api:sha-1 proxy:1.25,2024-05-17T14:30:00Z
+worker,0,,
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrintTable(t *testing.T) {
+	got := printWith(t, formatTable, "running,name")
+
+	want := `Running  Name
+2        api
+0        worker
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSelectUnknownColumn(t *testing.T) {
+	format, columns := formatJSON, "name,owner"
+	options := outputOptions{format: &format, columns: &columns}
+
+	err := options.print(&bytes.Buffer{}, testTable())
+	if err == nil || !strings.Contains(err.Error(), "available columns are: name,running,images,deployed") {
+		t.Errorf("got error %v, want the available columns", err)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	format, columns := "xml", ""
+	options := outputOptions{format: &format, columns: &columns}
+
+	if err := options.print(&bytes.Buffer{}, testTable()); err == nil {
+		t.Error("print as xml succeeded")
+	}
+}
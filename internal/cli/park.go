@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/park"
+)
+
+func parkCommand(args []string) error {
+	return runPark("park", args, park.Park)
+}
+
+func unparkCommand(args []string) error {
+	return runPark("unpark", args, park.Unpark)
+}
+
+// runPark parks or unparks the services in a cluster and prints the result of every service
+func runPark(name string, args []string, run func(clusterName string, progress func(park.Result)) error) error {
+	var clusterFlag *string
+	output, err := parseListFlags(name, args, func(flags *flag.FlagSet) {
+		clusterFlag = flags.String("cluster", "", "")
+	})
+	if err != nil {
+		return err
+	}
+
+	clusterName, err := resolveCluster(*clusterFlag)
+	if err != nil {
+		return err
+	}
+
+	results := make([]park.Result, 0)
+	if err := run(clusterName, func(result park.Result) {
+		results = append(results, result)
+	}); err != nil {
+		return err
+	}
+
+	t := table{columns: []column{
+		{key: "service", header: "Service"},
+		{key: "before", header: "Desired before"},
+		{key: "after", header: "Desired after"},
+		{key: "status", header: "Status"},
+		{key: "message", header: "Message"},
+	}}
+	for _, r := range results {
+		t.rows = append(t.rows, []any{r.Service, r.Before, r.After, r.Status, r.Message})
+	}
+
+	if err := output.print(stdout, t); err != nil {
+		return err
+	}
+
+	failed := lo.CountBy(results, func(r park.Result) bool { return r.Status == park.StatusFailed })
+	if failed > 0 {
+		return fmt.Errorf("%d of %d services failed in cluster %s", failed, len(results), clusterName)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func restartCommand(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "service":
+		return restartService(args[1:])
+	case "function":
+		return restartFunction(args[1:])
+	}
+
+	return errUsage
+}
+
+func restartService(args []string) error {
+	flags := flag.NewFlagSet("restart service", flag.ContinueOnError)
+	clusterFlag := flags.String("cluster", "", "")
+
+	positional, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	serviceName := positional[0]
+
+	clusterName, err := resolveCluster(*clusterFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := aws.RestartECSService(clusterName, serviceName); err != nil {
+		return fmt.Errorf("failed to restart service %s: %w", serviceName, err)
+	}
+
+	fmt.Fprintf(stdout, "ECS service %s restarted successfully\n", serviceName)
+
+	return nil
+}
+
+func restartFunction(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	functionName := args[0]
+
+	if err := aws.RestartLambdaFunction(functionName); err != nil {
+		return fmt.Errorf("failed to restart function %s: %w", functionName, err)
+	}
+
+	fmt.Fprintf(stdout, "Lambda function %s restarted successfully\n", functionName)
+
+	return nil
+}
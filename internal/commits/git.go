@@ -0,0 +1,51 @@
+package commits
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitProvider reads commits from a local git checkout
+type GitProvider struct {
+	Path   string
+	Branch string
+}
+
+func (p *GitProvider) Name() string {
+	return fmt.Sprintf("git: %s", p.Path)
+}
+
+func (p *GitProvider) List() ([]Commit, error) {
+	branch := p.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	path := p.Path
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+
+	output, err := exec.Command("git", "-C", path, "log", "-n", "20", "--format=%H%x1f%s", branch, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits from %s: %w", p.Path, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+
+	commits := make([]Commit, 0, len(lines))
+	for _, line := range lines {
+		sha, message, found := strings.Cut(line, "\x1f")
+		if !found {
+			continue
+		}
+		commits = append(commits, Commit{Sha: sha, Message: message})
+	}
+
+	return commits, nil
+}
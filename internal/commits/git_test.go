@@ -0,0 +1,49 @@
+package commits
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestGitList(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q")
+	git(t, dir, "commit", "-q", "--allow-empty", "-m", "Add orders")
+	git(t, dir, "commit", "-q", "--allow-empty", "-m", "Fix login")
+
+	commits, err := (&GitProvider{Path: dir}).List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+		if len(c.Sha) != 40 {
+			t.Errorf("got sha %q, want a full sha", c.Sha)
+		}
+	}
+	if want := []string{"Fix login", "Add orders"}; !slices.Equal(messages, want) {
+		t.Errorf("got %v, want %v", messages, want)
+	}
+}
+
+func TestGitListNotACheckout(t *testing.T) {
+	if _, err := (&GitProvider{Path: t.TempDir()}).List(); err == nil {
+		t.Error("List() outside a checkout succeeded")
+	}
+}
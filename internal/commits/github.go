@@ -0,0 +1,32 @@
+package commits
+
+import (
+	"fmt"
+
+	"github.com/bsek/s9k/internal/github"
+)
+
+// GitHubProvider reads commits from a GitHub repository
+type GitHubProvider struct {
+	Owner      string
+	Repository string
+	Branch     string
+}
+
+func (p *GitHubProvider) Name() string {
+	return fmt.Sprintf("github.com/%s/%s", p.Owner, p.Repository)
+}
+
+func (p *GitHubProvider) List() ([]Commit, error) {
+	list, err := github.FetchCommits(p.Owner, p.Repository, p.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0, len(list))
+	for _, c := range list {
+		commits = append(commits, Commit{Sha: c.Sha, Message: c.Message})
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,68 @@
+package commits
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GitLabProvider reads commits from a project on gitlab.com or a self-hosted GitLab instance
+type GitLabProvider struct {
+	URL     string
+	Token   string
+	Project string
+	Branch  string
+}
+
+func (p *GitLabProvider) Name() string {
+	return fmt.Sprintf("%s/%s", p.URL, p.Project)
+}
+
+func (p *GitLabProvider) List() ([]Commit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("per_page", "20")
+	if p.Branch != "" {
+		query.Set("ref_name", p.Branch)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/repository/commits?%s", p.URL, url.PathEscape(p.Project), query.Encode())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.Token != "" {
+		request.Header.Set("PRIVATE-TOKEN", p.Token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read commits from %s: %s", p.Name(), response.Status)
+	}
+
+	var list []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	commits := make([]Commit, 0, len(list))
+	for _, c := range list {
+		commits = append(commits, Commit{Sha: c.ID, Message: c.Message})
+	}
+
+	return commits, nil
+}
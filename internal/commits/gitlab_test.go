@@ -0,0 +1,48 @@
+package commits
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGitLabList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/team%2Fapi/repository/commits" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("ref_name") != "release" || r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[{"id": "b81a6f0", "message": "Fix login"}, {"id": "4f1c2ab", "message": "Add orders"}]`))
+	}))
+	defer server.Close()
+
+	provider := &GitLabProvider{URL: server.URL, Token: "secret", Project: "team/api", Branch: "release"}
+
+	commits, err := provider.List()
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	want := []Commit{{Sha: "b81a6f0", Message: "Fix login"}, {Sha: "4f1c2ab", Message: "Add orders"}}
+	if !slices.Equal(commits, want) {
+		t.Errorf("got %v, want %v", commits, want)
+	}
+}
+
+func TestGitLabListFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	provider := &GitLabProvider{URL: server.URL, Project: "team/missing"}
+
+	if _, err := provider.List(); err == nil {
+		t.Error("List() of a missing project succeeded")
+	}
+}
@@ -0,0 +1,69 @@
+package commits
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bsek/s9k/internal/config"
+	"github.com/bsek/s9k/internal/github"
+)
+
+// Commit is a single commit shown next to the deployables of a service
+type Commit struct {
+	Sha     string
+	Message string
+}
+
+// Provider lists the latest commits of the repository a single service is built from
+type Provider interface {
+	// Name describes the provider, e.g. the host and repository
+	Name() string
+	// List returns the latest commits, newest first
+	List() ([]Commit, error)
+}
+
+// ForService returns the commit provider configured for the given ECS service. Without configuration GitHub is
+// used if a GitHub token is available, otherwise no commits are listed
+func ForService(clusterName, serviceName string) (Provider, error) {
+	settings := config.ForService(clusterName, serviceName)
+
+	provider := settings.CommitProvider
+	if provider == "" {
+		provider = config.ProviderNone
+		if github.IsConfigured() {
+			provider = config.ProviderGitHub
+		}
+	}
+
+	switch provider {
+	case config.ProviderGitHub:
+		return &GitHubProvider{Owner: settings.GitHub.Owner, Repository: settings.CommitRepositoryName(), Branch: settings.GitHub.Branch}, nil
+	case config.ProviderGitLab:
+		project := settings.GitLabProjectPath()
+		if project == "" {
+			return nil, fmt.Errorf("no gitlab project configured for service %s", serviceName)
+		}
+		return &GitLabProvider{URL: strings.TrimSuffix(settings.GitLab.URL, "/"), Token: settings.GitLab.Token, Project: project, Branch: settings.GitLab.Branch}, nil
+	case config.ProviderGit:
+		path := settings.GitPath()
+		if path == "" {
+			return nil, fmt.Errorf("no git path configured for service %s", serviceName)
+		}
+		return &GitProvider{Path: path, Branch: settings.Git.Branch}, nil
+	case config.ProviderNone:
+		return None{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown commit provider %s", provider)
+}
+
+// None is used when commits should not be shown
+type None struct{}
+
+func (None) Name() string {
+	return "none"
+}
+
+func (None) List() ([]Commit, error) {
+	return nil, nil
+}
@@ -0,0 +1,74 @@
+package commits
+
+import (
+	"testing"
+
+	"github.com/bsek/s9k/internal/config"
+)
+
+func useConfig(t *testing.T, c *config.Config) {
+	t.Helper()
+
+	previous := config.Get()
+	config.Set(c)
+	t.Cleanup(func() { config.Set(previous) })
+}
+
+func TestForService(t *testing.T) {
+	c := config.Default()
+	c.Clusters = map[string]config.Cluster{
+		"dev": {
+			Settings: config.Settings{
+				CommitProvider: config.ProviderGitLab,
+				GitLab:         config.GitLab{URL: "https://gitlab.example.com/", Project: "team/{{.Service}}"},
+			},
+			Services: map[string]config.Settings{
+				"worker": {CommitProvider: config.ProviderGit, Git: config.Git{Path: "~/src/{{.Service}}"}},
+			},
+		},
+	}
+	useConfig(t, c)
+
+	provider, err := ForService("dev", "api")
+	if err != nil {
+		t.Fatalf("ForService() failed: %v", err)
+	}
+	if gitlab, ok := provider.(*GitLabProvider); !ok || gitlab.URL != "https://gitlab.example.com" || gitlab.Project != "team/api" || gitlab.Branch != "main" {
+		t.Errorf("got %#v, want the gitlab project team/api", provider)
+	}
+
+	provider, err = ForService("dev", "worker")
+	if err != nil {
+		t.Fatalf("ForService() failed: %v", err)
+	}
+	if git, ok := provider.(*GitProvider); !ok || git.Path != "~/src/worker" || git.Branch != "HEAD" {
+		t.Errorf("got %#v, want the git checkout ~/src/worker", provider)
+	}
+
+	// without a provider and without a GitHub token no commits are listed
+	provider, err = ForService("prod", "api")
+	if err != nil {
+		t.Fatalf("ForService() failed: %v", err)
+	}
+	if _, ok := provider.(None); !ok {
+		t.Errorf("got %#v, want none", provider)
+	}
+}
+
+func TestForServiceMissingSettings(t *testing.T) {
+	tests := []config.Settings{
+		{CommitProvider: config.ProviderGitLab},
+		{CommitProvider: config.ProviderGit},
+		{CommitProvider: "svn"},
+	}
+
+	for _, settings := range tests {
+		c := config.Default()
+		c.Clusters = map[string]config.Cluster{"dev": {Settings: settings}}
+		useConfig(t, c)
+
+		if provider, err := ForService("dev", "api"); err == nil {
+			t.Errorf("got %#v for %s, want an error", provider, settings.CommitProvider)
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of the s9k configuration file. Settings on the top level apply to every cluster, service
+// and lambda function, and can be overridden per cluster, per service within a cluster and per function
+type Config struct {
+	LogFile   string `yaml:"logFile"`
+	AWS       AWS    `yaml:"aws"`
+	Settings  `yaml:",inline"`
+	Clusters  map[string]Cluster  `yaml:"clusters"`
+	Functions map[string]Settings `yaml:"functions"`
+}
+
+// AWS holds the default AWS profile, region and endpoint. Command line flags and environment variables take
+// precedence over these values
+type AWS struct {
+	Profile     string `yaml:"profile"`
+	Region      string `yaml:"region"`
+	EndpointURL string `yaml:"endpointUrl"`
+	S3PathStyle bool   `yaml:"s3PathStyle"`
+}
+
+// Cluster holds the settings for a single ECS cluster and the services in it
+type Cluster struct {
+	Settings `yaml:",inline"`
+	Services map[string]Settings `yaml:"services"`
+}
+
+// Settings holds the naming conventions used to find images, commits and parameters for a service. Values that
+// are templates are rendered with the fields of TemplateData, e.g. "{{.Cluster}}-{{.Service}}"
+type Settings struct {
+	GitHub GitHub `yaml:"github"`
+	GitLab GitLab `yaml:"gitlab"`
+	Git    Git    `yaml:"git"`
+	// CommitProvider is where commit messages are read from, one of github, gitlab, git or none. Defaults to
+	// github when a GitHub token is available
+	CommitProvider string `yaml:"commitProvider"`
+	// ECRRepository is the name of the ECR repository holding the images of a service (template)
+	ECRRepository string `yaml:"ecrRepository"`
+	// ImageTagParameter is the SSM parameter updated with the image tag when a service is deployed (template)
+	ImageTagParameter string `yaml:"imageTagParameter"`
+	// SessionManagerRegion is the region passed to the session manager plugin, defaults to the current region
+	SessionManagerRegion string `yaml:"sessionManagerRegion"`
+	// ArtifactSource is where deployable versions are listed from, one of ecr, ghcr or s3. Defaults to ecr for
+	// services and s3 for lambda functions
+	ArtifactSource string `yaml:"artifactSource"`
+	// GHCRPackage is the name of the container package in the GitHub Container Registry (template)
+	GHCRPackage string `yaml:"ghcrPackage"`
+	// S3Bucket is the bucket holding zip files of lambda functions
+	S3Bucket string `yaml:"s3Bucket"`
+	// S3Prefix is the prefix of the zip files of a function in the bucket (template)
+	S3Prefix string `yaml:"s3Prefix"`
+}
+
+// Commit providers
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGit    = "git"
+	ProviderNone   = "none"
+)
+
+// Artifact sources
+const (
+	SourceECR  = "ecr"
+	SourceGHCR = "ghcr"
+	SourceS3   = "s3"
+)
+
+// GitHub holds the GitHub owners and repositories used for a service
+type GitHub struct {
+	// Owner is the user or organisation owning the repository with the commits of a service
+	Owner string `yaml:"owner"`
+	// Repository is the name of the repository with the commits of a service (template)
+	Repository string `yaml:"repository"`
+	// Branch is the branch to read commits from
+	Branch string `yaml:"branch"`
+	// PackagesOwner is the organisation owning the container packages in the GitHub Container Registry
+	PackagesOwner string `yaml:"packagesOwner"`
+	// WorkflowRepository and Workflow identify the workflow dispatched to update a container image version
+	WorkflowRepository string `yaml:"workflowRepository"`
+	Workflow           string `yaml:"workflow"`
+	WorkflowRef        string `yaml:"workflowRef"`
+	// Token is used instead of GITHUB_TOKEN, GH_TOKEN or the token of the gh tool. Only read from the top level
+	Token string `yaml:"token"`
+}
+
+// GitLab holds the GitLab instance and project used for a service
+type GitLab struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token"`
+	// Project is the path of the project with the commits of a service, e.g. "group/{{.Service}}" (template)
+	Project string `yaml:"project"`
+	Branch  string `yaml:"branch"`
+}
+
+// Git holds the local git checkout used for a service
+type Git struct {
+	// Path is the directory of the checkout with the commits of a service (template)
+	Path   string `yaml:"path"`
+	Branch string `yaml:"branch"`
+}
+
+// TemplateData is the data available to templated settings. Name is the name of the service or lambda function
+type TemplateData struct {
+	Cluster string
+	Service string
+	Name    string
+}
+
+var current = Default()
+
+// Default returns the configuration used when there is no configuration file
+func Default() *Config {
+	return &Config{
+		LogFile: filepath.Join(os.TempDir(), "s9k.log"),
+		Settings: Settings{
+			GitHub: GitHub{
+				Owner:              "oslohel",
+				Repository:         "fasit-{{.Service}}",
+				Branch:             "main",
+				PackagesOwner:      "oslokommune",
+				WorkflowRepository: "skjema-iac-terraform",
+				Workflow:           "update_container_image_version.yml",
+				WorkflowRef:        "master",
+			},
+			GitLab: GitLab{
+				URL:    "https://gitlab.com",
+				Branch: "main",
+			},
+			Git: Git{
+				Branch: "HEAD",
+			},
+			ECRRepository:     "{{.Cluster}}-{{.Service}}",
+			ImageTagParameter: "/{{.Cluster}}/ecs/{{.Service}}/image-tag",
+			GHCRPackage:       "{{.Name}}",
+			S3Bucket:          os.Getenv("S3_DEPLOYMENT_BUCKET_NAME"),
+			S3Prefix:          "{{.Name}}/",
+		},
+	}
+}
+
+// DefaultPath returns the location of the configuration file, $XDG_CONFIG_HOME/s9k/config.yaml or
+// ~/.config/s9k/config.yaml
+func DefaultPath() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+
+	return filepath.Join(dir, "config.yaml")
+}
+
+// Dir returns the directory of the configuration file and other files kept by s9k, $XDG_CONFIG_HOME/s9k or
+// ~/.config/s9k
+func Dir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "s9k")
+}
+
+// Load reads the configuration file at the given path on top of the given defaults, usually Default(). A missing
+// file is not an error when it is the default path
+func Load(path string, defaults *Config) (*Config, error) {
+	config := defaults
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+	if path == "" {
+		return config, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	var file Config
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if file.LogFile != "" {
+		config.LogFile = file.LogFile
+	}
+	config.AWS = file.AWS
+	config.Settings = config.Settings.merge(file.Settings)
+	config.Clusters = file.Clusters
+	config.Functions = file.Functions
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration in %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Set replaces the configuration used by the application
+func Set(config *Config) {
+	current = config
+}
+
+// Get returns the configuration used by the application
+func Get() *Config {
+	return current
+}
+
+// ForCluster returns the settings for the given cluster
+func ForCluster(clusterName string) Settings {
+	return current.ForCluster(clusterName)
+}
+
+// ForService returns the settings for the given service in the given cluster
+func ForService(clusterName, serviceName string) Service {
+	return current.ForService(clusterName, serviceName)
+}
+
+// ForFunction returns the settings for the given lambda function
+func ForFunction(functionName string) Service {
+	return current.ForFunction(functionName)
+}
+
+// ForCluster returns the top level settings overridden by the settings of the given cluster
+func (c *Config) ForCluster(clusterName string) Settings {
+	settings := c.Settings
+	if cluster, found := c.Clusters[clusterName]; found {
+		settings = settings.merge(cluster.Settings)
+	}
+	return settings
+}
+
+// ForService returns the settings of the given cluster overridden by the settings of the given service
+func (c *Config) ForService(clusterName, serviceName string) Service {
+	settings := c.ForCluster(clusterName)
+	if cluster, found := c.Clusters[clusterName]; found {
+		if service, found := cluster.Services[serviceName]; found {
+			settings = settings.merge(service)
+		}
+	}
+
+	return Service{
+		Settings: settings,
+		data:     TemplateData{Cluster: clusterName, Service: serviceName, Name: serviceName},
+	}
+}
+
+// ForFunction returns the top level settings overridden by the settings of the given lambda function
+func (c *Config) ForFunction(functionName string) Service {
+	settings := c.Settings
+	if function, found := c.Functions[functionName]; found {
+		settings = settings.merge(function)
+	}
+
+	return Service{
+		Settings: settings,
+		data:     TemplateData{Name: functionName},
+	}
+}
+
+// validate makes sure all templates in the configuration can be parsed and that artifact sources are known
+func (c *Config) validate() error {
+	all := []Settings{c.Settings}
+	for _, cluster := range c.Clusters {
+		all = append(all, cluster.Settings)
+		for _, service := range cluster.Services {
+			all = append(all, service)
+		}
+	}
+	for _, function := range c.Functions {
+		all = append(all, function)
+	}
+
+	for _, settings := range all {
+		switch settings.ArtifactSource {
+		case "", SourceECR, SourceGHCR, SourceS3:
+		default:
+			return fmt.Errorf("unknown artifact source %s", settings.ArtifactSource)
+		}
+
+		switch settings.CommitProvider {
+		case "", ProviderGitHub, ProviderGitLab, ProviderGit, ProviderNone:
+		default:
+			return fmt.Errorf("unknown commit provider %s", settings.CommitProvider)
+		}
+
+		for _, text := range []string{settings.ECRRepository, settings.ImageTagParameter, settings.GitHub.Repository, settings.GHCRPackage, settings.S3Prefix, settings.GitLab.Project, settings.Git.Path} {
+			if _, err := template.New("setting").Option("missingkey=error").Parse(text); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// merge returns the settings with every non empty value in override replacing the value in s
+func (s Settings) merge(override Settings) Settings {
+	s.GitHub.Owner = pick(s.GitHub.Owner, override.GitHub.Owner)
+	s.GitHub.Repository = pick(s.GitHub.Repository, override.GitHub.Repository)
+	s.GitHub.Branch = pick(s.GitHub.Branch, override.GitHub.Branch)
+	s.GitHub.PackagesOwner = pick(s.GitHub.PackagesOwner, override.GitHub.PackagesOwner)
+	s.GitHub.WorkflowRepository = pick(s.GitHub.WorkflowRepository, override.GitHub.WorkflowRepository)
+	s.GitHub.Workflow = pick(s.GitHub.Workflow, override.GitHub.Workflow)
+	s.GitHub.WorkflowRef = pick(s.GitHub.WorkflowRef, override.GitHub.WorkflowRef)
+	s.GitHub.Token = pick(s.GitHub.Token, override.GitHub.Token)
+	s.GitLab.URL = pick(s.GitLab.URL, override.GitLab.URL)
+	s.GitLab.Token = pick(s.GitLab.Token, override.GitLab.Token)
+	s.GitLab.Project = pick(s.GitLab.Project, override.GitLab.Project)
+	s.GitLab.Branch = pick(s.GitLab.Branch, override.GitLab.Branch)
+	s.Git.Path = pick(s.Git.Path, override.Git.Path)
+	s.Git.Branch = pick(s.Git.Branch, override.Git.Branch)
+	s.CommitProvider = pick(s.CommitProvider, override.CommitProvider)
+	s.ECRRepository = pick(s.ECRRepository, override.ECRRepository)
+	s.ImageTagParameter = pick(s.ImageTagParameter, override.ImageTagParameter)
+	s.SessionManagerRegion = pick(s.SessionManagerRegion, override.SessionManagerRegion)
+	s.ArtifactSource = pick(s.ArtifactSource, override.ArtifactSource)
+	s.GHCRPackage = pick(s.GHCRPackage, override.GHCRPackage)
+	s.S3Bucket = pick(s.S3Bucket, override.S3Bucket)
+	s.S3Prefix = pick(s.S3Prefix, override.S3Prefix)
+
+	return s
+}
+
+func pick(value, override string) string {
+	if override != "" {
+		return override
+	}
+	return value
+}
+
+// Service holds the resolved settings for a single service
+type Service struct {
+	Settings
+	data TemplateData
+}
+
+// ECRRepositoryName returns the name of the ECR repository holding the images of the service
+func (s Service) ECRRepositoryName() string {
+	return s.render(s.ECRRepository)
+}
+
+// ImageTagParameterName returns the name of the SSM parameter holding the image tag of the service
+func (s Service) ImageTagParameterName() string {
+	return s.render(s.ImageTagParameter)
+}
+
+// GitLabProjectPath returns the path of the GitLab project with the commits of the service
+func (s Service) GitLabProjectPath() string {
+	return s.render(s.GitLab.Project)
+}
+
+// GitPath returns the directory of the local git checkout with the commits of the service
+func (s Service) GitPath() string {
+	return s.render(s.Git.Path)
+}
+
+// GHCRPackageName returns the name of the container package in the GitHub Container Registry
+func (s Service) GHCRPackageName() string {
+	return s.render(s.GHCRPackage)
+}
+
+// S3KeyPrefix returns the prefix of the zip files of the function in the S3 bucket
+func (s Service) S3KeyPrefix() string {
+	return s.render(s.S3Prefix)
+}
+
+// CommitRepositoryName returns the name of the GitHub repository with the commits of the service
+func (s Service) CommitRepositoryName() string {
+	return s.render(s.GitHub.Repository)
+}
+
+func (s Service) render(text string) string {
+	t, err := template.New("setting").Option("missingkey=error").Parse(text)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to parse template [%s]", text)
+		return text
+	}
+
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, s.data); err != nil {
+		log.Error().Err(err).Msgf("Failed to render template [%s]", text)
+		return text
+	}
+
+	return buffer.String()
+}
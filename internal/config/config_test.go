@@ -0,0 +1,227 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const overrides = `
+logFile: /var/log/s9k.log
+github:
+  owner: acme
+ecrRepository: "{{.Service}}"
+clusters:
+  prod:
+    github:
+      owner: acme-prod
+    imageTagParameter: "/prod/{{.Service}}/tag"
+    services:
+      api:
+        github:
+          owner: acme-api
+          repository: api-server
+        ecrRepository: "prod/api"
+`
+
+func TestLoadOverrideOrder(t *testing.T) {
+	config, err := Load(writeConfig(t, overrides), Default())
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if config.LogFile != "/var/log/s9k.log" {
+		t.Errorf("got log file %s", config.LogFile)
+	}
+
+	tests := []struct {
+		cluster, service                            string
+		owner, repository, ecrRepository, parameter string
+	}{
+		// top level settings over the defaults
+		{"dev", "web", "acme", "fasit-web", "web", "/dev/ecs/web/image-tag"},
+		// cluster settings over the top level
+		{"prod", "web", "acme-prod", "fasit-web", "web", "/prod/web/tag"},
+		// service settings over the cluster
+		{"prod", "api", "acme-api", "api-server", "prod/api", "/prod/api/tag"},
+	}
+
+	for _, tt := range tests {
+		service := config.ForService(tt.cluster, tt.service)
+
+		if service.GitHub.Owner != tt.owner {
+			t.Errorf("%s/%s: got owner %s, want %s", tt.cluster, tt.service, service.GitHub.Owner, tt.owner)
+		}
+		if got := service.CommitRepositoryName(); got != tt.repository {
+			t.Errorf("%s/%s: got repository %s, want %s", tt.cluster, tt.service, got, tt.repository)
+		}
+		if got := service.ECRRepositoryName(); got != tt.ecrRepository {
+			t.Errorf("%s/%s: got ecr repository %s, want %s", tt.cluster, tt.service, got, tt.ecrRepository)
+		}
+		if got := service.ImageTagParameterName(); got != tt.parameter {
+			t.Errorf("%s/%s: got image tag parameter %s, want %s", tt.cluster, tt.service, got, tt.parameter)
+		}
+	}
+
+	// defaults that are not overridden are kept
+	if got := config.ForCluster("prod").GitHub.Branch; got != "main" {
+		t.Errorf("got branch %s, want the default", got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	config, err := Load("", Default())
+	if err != nil {
+		t.Fatalf("Load() without a default file failed: %v", err)
+	}
+	if got := config.ForService("dev", "api").ECRRepositoryName(); got != "dev-api" {
+		t.Errorf("got ecr repository %s, want the default", got)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml"), Default()); err == nil {
+		t.Error("Load() of a missing file given on the command line succeeded")
+	}
+}
+
+func TestLoadDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "s9k"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "s9k", "config.yaml"), []byte("github:\n  owner: acme\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load("", Default())
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if config.GitHub.Owner != "acme" {
+		t.Errorf("got owner %s, want the owner from the default file", config.GitHub.Owner)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not yaml":                "github: [",
+		"invalid template":        `ecrRepository: "{{.Service"`,
+		"invalid service setting": "clusters:\n  dev:\n    services:\n      api:\n        imageTagParameter: \"{{end}}\"\n",
+	}
+
+	for name, content := range tests {
+		if _, err := Load(writeConfig(t, content), Default()); err == nil {
+			t.Errorf("%s: Load() succeeded", name)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	config := Default()
+	config.ImageTagParameter = "/{{.Cluster}}/{{.Service}}/{{.Unknown}}"
+
+	service := config.ForService("dev", "api")
+
+	// a template that can not be rendered is used as it is
+	if got := service.ImageTagParameterName(); got != config.ImageTagParameter {
+		t.Errorf("got %s, want the template as it is", got)
+	}
+	if got := service.ECRRepositoryName(); got != "dev-api" {
+		t.Errorf("got ecr repository %s, want dev-api", got)
+	}
+	if got := service.CommitRepositoryName(); got != "fasit-api" {
+		t.Errorf("got repository %s, want fasit-api", got)
+	}
+}
+
+func TestLoadOnTopOfDefaults(t *testing.T) {
+	defaults := Default()
+	defaults.S3Bucket = "s9k-deployments"
+
+	config, err := Load(writeConfig(t, "ecrRepository: \"{{.Service}}\"\n"), defaults)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if config.S3Bucket != "s9k-deployments" || config.ECRRepository != "{{.Service}}" {
+		t.Errorf("got bucket %q and repository %q, want the default bucket and the configured repository", config.S3Bucket, config.ECRRepository)
+	}
+
+	config, err = Load(writeConfig(t, "s3Bucket: deployments\n"), defaults)
+	if err != nil || config.S3Bucket != "deployments" {
+		t.Errorf("got bucket %q and error %v, want the configured bucket", config.S3Bucket, err)
+	}
+}
+
+func TestLoadFunctionOverrides(t *testing.T) {
+	config, err := Load(writeConfig(t, `
+s3Bucket: deployments
+artifactSource: ghcr
+clusters:
+  dev:
+    artifactSource: ecr
+functions:
+  email-sender:
+    s3Prefix: "lambdas/{{.Name}}-"
+    artifactSource: s3
+`), Default())
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	function := config.ForFunction("email-sender")
+	if function.ArtifactSource != SourceS3 || function.S3Bucket != "deployments" {
+		t.Errorf("got source %s from bucket %s, want s3 from deployments", function.ArtifactSource, function.S3Bucket)
+	}
+	if got := function.S3KeyPrefix(); got != "lambdas/email-sender-" {
+		t.Errorf("got prefix %s, want lambdas/email-sender-", got)
+	}
+
+	// functions do not take the settings of a cluster
+	other := config.ForFunction("orders-handler")
+	if other.ArtifactSource != SourceGHCR {
+		t.Errorf("got source %s for a function without settings, want the top level ghcr", other.ArtifactSource)
+	}
+	if got := other.S3KeyPrefix(); got != "orders-handler/" {
+		t.Errorf("got prefix %s, want the default", got)
+	}
+
+	if got := config.ForService("dev", "api").ArtifactSource; got != SourceECR {
+		t.Errorf("got source %s for a service in dev, want ecr", got)
+	}
+}
+
+func TestLoadUnknownArtifactSource(t *testing.T) {
+	tests := []string{
+		"artifactSource: docker\n",
+		"clusters:\n  dev:\n    services:\n      api:\n        artifactSource: docker\n",
+		"functions:\n  email-sender:\n    artifactSource: docker\n",
+	}
+
+	for _, content := range tests {
+		_, err := Load(writeConfig(t, content), Default())
+		if err == nil || !strings.Contains(err.Error(), "unknown artifact source docker") {
+			t.Errorf("got error %v for %q, want unknown artifact source", err, content)
+		}
+	}
+}
+
+func TestLoadUnknownCommitProvider(t *testing.T) {
+	_, err := Load(writeConfig(t, "clusters:\n  dev:\n    commitProvider: svn\n"), Default())
+	if err == nil || !strings.Contains(err.Error(), "unknown commit provider svn") {
+		t.Errorf("got error %v, want unknown commit provider", err)
+	}
+}
@@ -1,10 +1,13 @@
 package data
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/rs/zerolog/log"
@@ -16,62 +19,158 @@ import (
 
 const MAX_IMAGE_WIDTH = 50
 
+// functionDetailsWorkers is the number of lambda functions whose details are read at the same time
+const functionDetailsWorkers = 8
+
 func NewAccountData(clusterName string, account string) *AccountData {
 	return &AccountData{
 		Functions:   nil,
+		Clusters:    nil,
 		ClusterName: clusterName,
 		ClusterData: nil,
 		Refreshed:   time.Now(),
 		AccountId:   account,
+		Profile:     aws.CurrentProfile(),
+		Region:      aws.CurrentRegion(),
 		Apis:        nil,
 	}
 }
 
-func (d *AccountData) Refresh() {
-	ecsClusterData := loadECSClusterData(d.ClusterName)
-	lambdaFunctions := loadLambdaFunctions()
-	apis := loadApis()
+// Refresh reads the data of the account again. The data is left as it was if the services of the cluster or the
+// lambda functions can not be read
+func (d *AccountData) Refresh() error {
+	clusters := loadECSClusters()
+
+	ecsClusterData, err := LoadECSClusterData(d.ClusterName)
+	if err != nil {
+		return fmt.Errorf("failed to load ECS cluster data of %s: %w", d.ClusterName, err)
+	}
+
+	lambdaFunctions, err := LoadFunctions()
+	if err != nil {
+		return fmt.Errorf("failed to load lambda functions: %w", err)
+	}
+
+	apis := LoadApis()
 	d.Functions = lambdaFunctions
+	d.Clusters = clusters
 	d.ClusterData = ecsClusterData
 	d.Apis = apis
 	d.Refreshed = time.Now()
+
+	return nil
+}
+
+func loadECSClusters() []ecsTypes.Cluster {
+	clusters, err := LoadECSClusters()
+	if err != nil {
+		log.Error().Err(err).Msg("An issue occurred when loading ECS clusters")
+		return nil
+	}
+
+	return clusters
+}
+
+// LoadECSClusters reads all ECS clusters in the account, sorted by name
+func LoadECSClusters() ([]ecsTypes.Cluster, error) {
+	clusters, err := aws.ListECSClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return 0 > strings.Compare(*clusters[i].ClusterName, *clusters[j].ClusterName)
+	})
+
+	return clusters, nil
 }
 
-func loadApis() []aws.ApiGateway {
+// LoadApis reads all api gateway apis in the account
+func LoadApis() []aws.ApiGateway {
 	apis := aws.FetchApis()
 	log.Debug().Msgf("Found gateways: %v", apis)
 	return apis
 }
 
-func loadLambdaFunctions() []Function {
+// LoadFunctions reads all lambda functions in the account with their tags and concurrency, sorted by name
+func LoadFunctions() ([]Function, error) {
 	functionsResult, err := aws.ListLambdaFunctions()
 	if err != nil {
-		log.Fatal().Err(err).Msg("An issue occurred when loading lambda functions")
+		return nil, err
 	}
 
 	sort.SliceStable(functionsResult, func(i int, j int) bool {
 		return 0 > strings.Compare(*functionsResult[i].FunctionName, *functionsResult[j].FunctionName)
 	})
 
-	functions := lo.Map(functionsResult, func(item lambdaTypes.FunctionConfiguration, _ int) Function {
-		output, err := aws.GetLambdaFunction(*item.FunctionArn)
-		if err != nil {
-			log.Error().Err(err).Msgf("Failed to read lambda function details for function %s", *item.FunctionName)
-		}
-		return Function{
-			output.Tags,
-			item,
+	// the details take a few calls per function, they are read for several functions at a time
+	functions := make([]Function, len(functionsResult))
+	limit := make(chan struct{}, functionDetailsWorkers)
+	var wg sync.WaitGroup
+	for i, item := range functionsResult {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			functions[i] = loadFunctionDetails(item)
+		}()
+	}
+	wg.Wait()
+
+	names := lo.Map(functions, func(function Function, _ int) string { return *function.FunctionName })
+	rates, err := aws.FetchLambdaErrorRates(names, time.Hour)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read error rates of lambda functions")
+	}
+	for i, function := range functions {
+		if rate, found := rates[*function.FunctionName]; found {
+			functions[i].ErrorRate = &rate
 		}
-	})
+	}
+
+	return functions, nil
+}
+
+// loadFunctionDetails reads the tags and concurrency of a lambda function. The function is returned without them
+// if they can not be read
+func loadFunctionDetails(item lambdaTypes.FunctionConfiguration) Function {
+	function := Function{FunctionConfiguration: item}
 
-	return functions
+	output, err := aws.GetLambdaFunction(*item.FunctionArn)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read lambda function details for function %s", *item.FunctionName)
+		return function
+	}
+	function.Tags = output.Tags
+	if output.Concurrency != nil {
+		function.ReservedConcurrency = output.Concurrency.ReservedConcurrentExecutions
+	}
+
+	provisioned, err := aws.ListProvisionedConcurrency(*item.FunctionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read provisioned concurrency for function %s", *item.FunctionName)
+	}
+	for _, config := range provisioned {
+		function.ProvisionedConcurrency += awssdk.ToInt32(config.RequestedProvisionedConcurrentExecutions)
+	}
+
+	return function
 }
 
-func loadECSClusterData(clusterName string) *ECSClusterData {
+// LoadECSClusterData reads the services in the given ECS cluster with the containers of their task definitions,
+// sorted by name
+func LoadECSClusterData(clusterName string) (*ECSClusterData, error) {
+	// Accounts without any ECS clusters have no services to load
+	if clusterName == "" {
+		return &ECSClusterData{}, nil
+	}
+
 	// Read all services
 	services, err := aws.DescribeClusterServices(clusterName)
 	if err != nil {
-		log.Fatal().Err(err).Msg("An issue occurred when loading ECS cluster data")
+		return nil, err
 	}
 
 	sort.SliceStable(services, func(i, j int) bool {
@@ -86,7 +185,7 @@ func loadECSClusterData(clusterName string) *ECSClusterData {
 
 	taskDefinitions, err := aws.GetTaskDefinitions(taskDefinitionArns)
 	if err != nil {
-		log.Fatal().Err(err).Msg("An issue occurred when loading ECS task definitions")
+		return nil, err
 	}
 
 	taskDefinitionArnLookup := make(map[string]ecsTypes.TaskDefinition)
@@ -123,5 +222,5 @@ func loadECSClusterData(clusterName string) *ECSClusterData {
 		Services: serviceDataList,
 	}
 
-	return data
+	return data, nil
 }
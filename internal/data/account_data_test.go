@@ -0,0 +1,118 @@
+package data
+
+import (
+	"slices"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/bsek/s9k/internal/aws/fake"
+)
+
+func TestRefresh(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	d := NewAccountData("dev", "123456789012")
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+
+	clusters := make([]string, 0, len(d.Clusters))
+	for _, c := range d.Clusters {
+		clusters = append(clusters, *c.ClusterName)
+	}
+	if want := []string{"dev", "prod", "test"}; !slices.Equal(clusters, want) {
+		t.Errorf("got clusters %v, want %v", clusters, want)
+	}
+
+	services := serviceNames(d.ClusterData)
+	if want := []string{"api", "web", "worker"}; !slices.Equal(services, want) {
+		t.Errorf("got services %v, want %v", services, want)
+	}
+
+	functions := make([]string, 0, len(d.Functions))
+	for _, f := range d.Functions {
+		functions = append(functions, *f.FunctionName)
+	}
+	if want := []string{"email-sender", "orders-handler", "report-generator"}; !slices.Equal(functions, want) {
+		t.Errorf("got functions %v, want %v", functions, want)
+	}
+	if got := d.Functions[0].Tags["LastDeployed"]; got == "" {
+		t.Error("got no LastDeployed tag on email-sender")
+	}
+
+	if len(d.Apis) == 0 {
+		t.Error("got no apis")
+	}
+}
+
+func TestRefreshReadsFunctionDetails(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	d := NewAccountData("dev", "123456789012")
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+
+	for _, function := range d.Functions {
+		if *function.FunctionName != "orders-handler" {
+			if function.ReservedConcurrency != nil || function.ProvisionedConcurrency != 0 {
+				t.Errorf("got concurrency on %s, want none", *function.FunctionName)
+			}
+			continue
+		}
+		if awssdk.ToInt32(function.ReservedConcurrency) != 100 || function.ProvisionedConcurrency != 5 {
+			t.Errorf("got reserved %v and provisioned %d on orders-handler, want 100 and 5",
+				function.ReservedConcurrency, function.ProvisionedConcurrency)
+		}
+		if function.Tags["LastDeployed"] == "" {
+			t.Error("got no tags on orders-handler")
+		}
+	}
+}
+
+func TestRefreshWithoutClusters(t *testing.T) {
+	fake.NewBackend(fake.Fixtures{AccountId: "123456789012", Region: "eu-north-1"}).Install()
+
+	d := NewAccountData("", "123456789012")
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+
+	if d.ClusterData == nil || len(d.ClusterData.Services) != 0 {
+		t.Errorf("got cluster data %v, want no services", d.ClusterData)
+	}
+}
+
+func TestRefreshKeepsDataWhenClusterIsMissing(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	d := NewAccountData("dev", "123456789012")
+	if err := d.Refresh(); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	refreshed := d.Refreshed
+
+	d.ClusterName = "missing"
+	if err := d.Refresh(); err == nil {
+		t.Fatal("Refresh() of a missing cluster succeeded")
+	}
+
+	if services := serviceNames(d.ClusterData); len(services) != 3 {
+		t.Errorf("got services %v, want the services of dev", services)
+	}
+	if !d.Refreshed.Equal(refreshed) {
+		t.Error("the refresh time changed")
+	}
+}
+
+func serviceNames(clusterData *ECSClusterData) []string {
+	names := make([]string, 0)
+	if clusterData == nil {
+		return names
+	}
+	for _, s := range clusterData.Services {
+		names = append(names, awssdk.ToString(s.Service.ServiceName))
+	}
+	return names
+}
@@ -21,10 +21,13 @@ type ECSClusterData struct {
 
 type AccountData struct {
 	Functions   []Function
+	Clusters    []ecsTypes.Cluster
 	ClusterData *ECSClusterData
 	ClusterName string
 	Refreshed   time.Time
 	AccountId   string
+	Profile     string
+	Region      string
 	Apis        []aws.ApiGateway
 }
 
@@ -37,5 +40,11 @@ type Container struct {
 
 type Function struct {
 	Tags map[string]string
+	// ReservedConcurrency is nil when the function uses the unreserved concurrency of the account
+	ReservedConcurrency *int32
+	// ProvisionedConcurrency is the sum of the provisioned concurrency requested on the aliases and versions
+	ProvisionedConcurrency int32
+	// ErrorRate is the percentage of failed invocations in the last hour, nil when the function was not invoked
+	ErrorRate *float64
 	lambdaTypes.FunctionConfiguration
 }
@@ -0,0 +1,153 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+var _ ui.ContentPage = (*ClustersPage)(nil)
+
+type ClustersPage struct {
+	clustersTable *tview.Table
+	name          string
+}
+
+// Returns a page that lists the ECS clusters in the account. The selected function is called with the
+// name of the cluster chosen by the user
+func NewClustersPage(selected func(clusterName string)) *ClustersPage {
+	clustersTable := tview.NewTable()
+
+	clustersTable.
+		SetBorder(true).
+		SetTitle(" 🗄 ECS Clusters ")
+
+	clustersTable.SetSelectable(true, false)
+
+	clustersTable.SetSelectedFunc(func(row, _ int) {
+		cluster, found := clustersTable.GetCell(row, 1).Reference.(types.Cluster)
+		if found {
+			selected(*cluster.ClusterName)
+		}
+	})
+
+	clustersTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		row, _ := clustersTable.GetSelection()
+		cluster, found := clustersTable.GetCell(row, 1).Reference.(types.Cluster)
+		if !found {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'p', 'P':
+			confirmPark(*cluster.ClusterName)
+		case 'o', 'O':
+			confirmUnpark(*cluster.ClusterName)
+		}
+
+		return event
+	})
+
+	return &ClustersPage{
+		name:          "clusters",
+		clustersTable: clustersTable,
+	}
+}
+
+func (p *ClustersPage) Render(accountData *data.AccountData) {
+	p.clustersTable.Clear()
+
+	clusters := accountData.Clusters
+
+	if len(clusters) == 0 {
+		return
+	}
+
+	clusterData := lo.Map(clusters, func(cluster types.Cluster, _ int) []string {
+		current := ""
+		if *cluster.ClusterName == accountData.ClusterName {
+			current = "*"
+		}
+
+		return []string{
+			*cluster.ClusterName,
+			current,
+			utils.LowerTitle(*cluster.Status),
+			utils.I32ToString(cluster.ActiveServicesCount),
+			utils.I32ToString(cluster.RunningTasksCount),
+			utils.I32ToString(cluster.PendingTasksCount),
+			clusterStatistic(cluster, "runningFargateTasksCount"),
+			clusterStatistic(cluster, "runningEC2TasksCount"),
+			utils.I32ToString(cluster.RegisteredContainerInstancesCount),
+		}
+	})
+
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignCenter, tview.AlignLeft, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight}
+	expansions := []int{1, 2, 1, 1, 1, 1, 1, 1, 1, 1}
+	headers := []string{"#", "Name ▾", "Current", "Status", "Services", "Running tasks", "Pending tasks", "Fargate tasks", "EC2 tasks", "Container instances"}
+
+	ui.PrependRowNumColumn(clusterData)
+	ui.AddTableData(p.clustersTable, headers, clusterData, alignment, expansions, tcell.ColorWhite, true)
+
+	// set reference to cluster
+	for i := 1; i < len(clusters)+1; i++ {
+		cell := p.clustersTable.GetCell(i, 1)
+		cell.SetReference(clusters[i-1])
+	}
+}
+
+// clusterStatistic returns the value of the named statistic included when describing clusters, or n/a if missing
+func clusterStatistic(cluster types.Cluster, name string) string {
+	for _, v := range cluster.Statistics {
+		if v.Name != nil && *v.Name == name && v.Value != nil {
+			return *v.Value
+		}
+	}
+	return "n/a"
+}
+
+func (p *ClustersPage) Name() string {
+	return p.name
+}
+
+func (p *ClustersPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	bw := tw.BatchWriter()
+	defer bw.Close()
+
+	fmt.Fprintln(bw, "[white::b]Enter [darkcyan::-]Switch to cluster")
+	fmt.Fprintln(bw, "[white::b]p [darkcyan::-]Park cluster")
+	fmt.Fprintln(bw, "[white::b]o [darkcyan::-]Unpark cluster")
+
+	return tw
+}
+
+func (p *ClustersPage) SetFocus(app *tview.Application) {
+	app.SetFocus(p.clustersTable)
+}
+
+func (p *ClustersPage) View() tview.Primitive {
+	return p.clustersTable
+}
+
+func (p *ClustersPage) IsPersistent() bool {
+	return true
+}
+
+func (p *ClustersPage) Close() {
+}
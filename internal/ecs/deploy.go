@@ -7,7 +7,9 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/config"
 	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
 )
 
 func deploy(clusterName, serviceName, version string) {
@@ -19,13 +21,17 @@ func deploy(clusterName, serviceName, version string) {
 		SetDoneFunc(func(_ int, buttonLabel string) {
 			if buttonLabel == "Deploy" {
 				log.Debug().Msgf("Deploying version: [%s] for service [%s]", version, serviceName)
-				err := aws.UpdateECSImage(version, serviceName, clusterName)
+				err := aws.UpdateECSImage(version, serviceName, clusterName, config.ForService(clusterName, serviceName).ImageTagParameterName())
+				ui.App.Content.RemovePage(DEPLOY_DIALOG)
 				if err != nil {
 					ui.CreateMessageBox("Failed to deploy version, check log file")
-				} else {
-					ui.CreateMessageBox("Deploy request successfully sent")
+					return
 				}
-				ui.App.Content.RemovePage(DEPLOY_DIALOG)
+
+				// follow the rollout on the deployment page
+				page := NewDeploymentPage(clusterName, serviceName, utils.RemoveAllBeforeLastChar("/", &version))
+				ui.App.RegisterContent(page)
+				ui.App.ShowPage(page)
 			}
 			if buttonLabel == "Cancel" {
 				ui.App.Content.RemovePage(DEPLOY_DIALOG)
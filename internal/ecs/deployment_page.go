@@ -0,0 +1,341 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+var _ ui.ContentPage = (*DeploymentPage)(nil)
+
+// deploymentPollInterval is the time between each poll of the service while a deployment is tracked
+const deploymentPollInterval = 5 * time.Second
+
+// maxDeploymentEvents is the number of service events shown on the deployment page
+const maxDeploymentEvents = 20
+
+// deploymentOutcome is the state of a tracked deployment
+type deploymentOutcome int
+
+const (
+	deploymentInProgress deploymentOutcome = iota
+	deploymentSucceeded
+	deploymentFailed
+)
+
+// DeploymentPage tracks the rollout of a deployment to an ECS service. The service is polled until the deployment
+// is completed or has failed, and a notice is shown with the result
+type DeploymentPage struct {
+	Flex         *tview.Flex
+	status       *tview.TextView
+	deployments  *tview.Table
+	events       *tview.Table
+	clusterName  string
+	serviceName  string
+	description  string
+	deploymentId string
+	started      time.Time
+	cancel       context.CancelFunc
+}
+
+// NewDeploymentPage returns a page that tracks the current deployment of the service. The description is shown
+// in the header of the page, e.g. the image that is deployed
+func NewDeploymentPage(clusterName, serviceName, description string) *DeploymentPage {
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
+	status.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🚀 Deploying %s ", serviceName))
+
+	deployments := tview.NewTable().SetSelectable(true, false)
+	deployments.SetBorder(true).
+		SetTitle(" 📋 Deployments ")
+
+	events := tview.NewTable().SetSelectable(true, false)
+	events.SetBorder(true).
+		SetTitle(" 📜 Events ")
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(status, 6, 1, false).
+		AddItem(deployments, 0, 1, true).
+		AddItem(events, 0, 2, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	page := &DeploymentPage{
+		Flex:        flex,
+		status:      status,
+		deployments: deployments,
+		events:      events,
+		clusterName: clusterName,
+		serviceName: serviceName,
+		description: description,
+		started:     time.Now(),
+		cancel:      cancel,
+	}
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			if deployments.HasFocus() {
+				ui.App.TviewApp.SetFocus(events)
+			} else {
+				ui.App.TviewApp.SetFocus(deployments)
+			}
+		}
+		return event
+	})
+
+	go page.poll(ctx)
+
+	return page
+}
+
+// poll describes the service until the deployment is finished or the page is closed
+func (p *DeploymentPage) poll(ctx context.Context) {
+	ticker := time.NewTicker(deploymentPollInterval)
+	defer ticker.Stop()
+
+	for {
+		service, err := aws.DescribeService(p.clusterName, p.serviceName)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to describe service %s", p.serviceName)
+		}
+
+		outcome, reason := deploymentInProgress, ""
+		if err == nil {
+			outcome, reason = p.evaluate(service)
+		}
+
+		ui.App.TviewApp.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				p.renderError(err)
+				return
+			}
+
+			p.renderStatus(service, outcome, reason)
+			p.renderDeployments(service.Deployments)
+			p.renderEvents(service.Events)
+
+			if outcome != deploymentInProgress {
+				p.notify(service, outcome)
+			}
+		})
+
+		if outcome != deploymentInProgress {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate returns the state of the tracked deployment and the reason given by ECS. A deployment that is no
+// longer primary has been rolled back by the circuit breaker or replaced by another deployment
+func (p *DeploymentPage) evaluate(service *types.Service) (deploymentOutcome, string) {
+	// the deployment that is primary when the service is first described is the one started by the deploy
+	if p.deploymentId == "" {
+		if primary, found := findPrimaryDeployment(service.Deployments); found {
+			p.deploymentId = awssdk.ToString(primary.Id)
+		}
+	}
+
+	deployment, found := lo.Find(service.Deployments, func(d types.Deployment) bool {
+		return awssdk.ToString(d.Id) == p.deploymentId
+	})
+	if !found {
+		return deploymentFailed, "The deployment is no longer active"
+	}
+
+	reason := awssdk.ToString(deployment.RolloutStateReason)
+
+	switch {
+	case deployment.RolloutState == types.DeploymentRolloutStateFailed:
+		return deploymentFailed, reason
+	case awssdk.ToString(deployment.Status) != "PRIMARY":
+		return deploymentFailed, fmt.Sprintf("The deployment was replaced by another deployment. %s", reason)
+	case deployment.RolloutState == types.DeploymentRolloutStateCompleted && len(service.Deployments) == 1:
+		return deploymentSucceeded, reason
+	}
+
+	return deploymentInProgress, reason
+}
+
+func (p *DeploymentPage) renderStatus(service *types.Service, outcome deploymentOutcome, reason string) {
+	p.status.Clear()
+
+	bw := p.status.BatchWriter()
+	defer bw.Close()
+
+	state := fmt.Sprintf("[yellow::b]In progress[-::-] (%s)", time.Since(p.started).Round(time.Second))
+	switch outcome {
+	case deploymentSucceeded:
+		state = "[green::b]Completed[-::-]"
+	case deploymentFailed:
+		state = "[red::b]Failed[-::-]"
+	}
+
+	fmt.Fprintf(bw, "[darkcyan::b]Deploying:[-::-] %s to %s in %s\n", p.description, p.serviceName, p.clusterName)
+	fmt.Fprintf(bw, "[darkcyan::b]Rollout:[-::-] %s %s\n", state, reason)
+	fmt.Fprintf(bw, "[darkcyan::b]Tasks:[-::-] %d running, %d pending, %d desired\n", service.RunningCount, service.PendingCount, service.DesiredCount)
+	fmt.Fprintf(bw, "[darkcyan::b]Circuit breaker:[-::-] %s", circuitBreakerStatus(service.DeploymentConfiguration))
+}
+
+func (p *DeploymentPage) renderError(err error) {
+	p.status.Clear()
+	fmt.Fprintf(p.status, "[red::b]Failed to read service %s:[-::-] %s\nRetrying in %s", p.serviceName, err, deploymentPollInterval)
+}
+
+func (p *DeploymentPage) renderDeployments(deployments []types.Deployment) {
+	p.deployments.Clear()
+
+	tableData := [][]string{}
+	for _, d := range deployments {
+		id := awssdk.ToString(d.Id)
+		if id == p.deploymentId {
+			id = fmt.Sprintf("%s *", id)
+		}
+
+		tableData = append(tableData, []string{
+			id,
+			utils.LowerTitle(awssdk.ToString(d.Status)),
+			utils.LowerTitle(string(d.RolloutState)),
+			utils.RemoveAllBeforeLastChar("/", d.TaskDefinition),
+			utils.I32ToString(d.DesiredCount),
+			utils.I32ToString(d.RunningCount),
+			utils.I32ToString(d.PendingCount),
+			utils.I32ToString(d.FailedTasks),
+			formatTime(d.CreatedAt),
+			formatTime(d.UpdatedAt),
+		})
+	}
+
+	headers := []string{"Id", "Status", "Rollout state", "Task definition", "Desired", "Running", "Pending", "Failed", "Created", "Updated"}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{2, 1, 1, 2, 1, 1, 1, 1, 1, 1}
+
+	ui.AddTableData(p.deployments, headers, tableData, alignment, expansions, tcell.ColorGreenYellow, true)
+
+	// failed tasks are highlighted
+	for row, d := range deployments {
+		if d.FailedTasks > 0 {
+			p.deployments.GetCell(row+1, 7).SetTextColor(tcell.ColorRed)
+		}
+	}
+}
+
+func (p *DeploymentPage) renderEvents(events []types.ServiceEvent) {
+	p.events.Clear()
+
+	tableData := [][]string{}
+	for _, e := range lo.Slice(events, 0, maxDeploymentEvents) {
+		tableData = append(tableData, []string{
+			formatTime(e.CreatedAt),
+			awssdk.ToString(e.Message),
+		})
+	}
+
+	headers := []string{"Time", "Message"}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{1, 5}
+
+	ui.AddTableData(p.events, headers, tableData, alignment, expansions, tcell.ColorLightBlue, true)
+}
+
+// notify shows a message box with the result of the deployment
+func (p *DeploymentPage) notify(service *types.Service, outcome deploymentOutcome) {
+	if outcome == deploymentSucceeded {
+		ui.CreateMessageBox(fmt.Sprintf("Deployment of %s to %s completed successfully in %s",
+			p.description, p.serviceName, time.Since(p.started).Round(time.Second)))
+		return
+	}
+
+	message := fmt.Sprintf("Deployment of %s to %s failed", p.description, p.serviceName)
+	if primary, found := findPrimaryDeployment(service.Deployments); found && awssdk.ToString(primary.Id) != p.deploymentId {
+		message = fmt.Sprintf("%s, the service is rolled back to %s", message, utils.RemoveAllBeforeLastChar("/", primary.TaskDefinition))
+	}
+
+	ui.CreateMessageBox(message)
+}
+
+func findPrimaryDeployment(deployments []types.Deployment) (types.Deployment, bool) {
+	return lo.Find(deployments, func(d types.Deployment) bool {
+		return awssdk.ToString(d.Status) == "PRIMARY"
+	})
+}
+
+func circuitBreakerStatus(configuration *types.DeploymentConfiguration) string {
+	if configuration == nil || configuration.DeploymentCircuitBreaker == nil || !configuration.DeploymentCircuitBreaker.Enable {
+		return "disabled"
+	}
+	if configuration.DeploymentCircuitBreaker.Rollback {
+		return "enabled, rollback on failure"
+	}
+	return "enabled, no rollback"
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "n/a"
+	}
+	return utils.FormatLocalDateTime(*t)
+}
+
+func (*DeploymentPage) Name() string {
+	return "monitor deployment"
+}
+
+func (*DeploymentPage) Render(accountData *data.AccountData) {
+}
+
+func (p *DeploymentPage) View() tview.Primitive {
+	return p.Flex
+}
+
+func (p *DeploymentPage) Close() {
+	p.cancel()
+}
+
+func (p *DeploymentPage) IsPersistent() bool {
+	return false
+}
+
+func (p *DeploymentPage) SetFocus(app *tview.Application) {
+	app.SetFocus(p.deployments)
+}
+
+func (*DeploymentPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	bw := tw.BatchWriter()
+	defer bw.Close()
+
+	fmt.Fprintln(bw, "[white::b]Tab [darkcyan::-]Select view")
+	fmt.Fprintln(bw, "")
+	fmt.Fprintln(bw, "[white::b]d [darkcyan::-]Service details")
+
+	return tw
+}
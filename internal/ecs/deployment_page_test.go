@@ -0,0 +1,117 @@
+package ecs
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func deployment(id, status string, rolloutState types.DeploymentRolloutState) types.Deployment {
+	return types.Deployment{
+		Id:                 awssdk.String(id),
+		Status:             awssdk.String(status),
+		RolloutState:       rolloutState,
+		RolloutStateReason: awssdk.String(id + " reason"),
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name        string
+		deployments []types.Deployment
+		want        deploymentOutcome
+		wantReason  string
+	}{
+		{
+			name: "in progress",
+			deployments: []types.Deployment{
+				deployment("new", "PRIMARY", types.DeploymentRolloutStateInProgress),
+				deployment("old", "ACTIVE", types.DeploymentRolloutStateCompleted),
+			},
+			want:       deploymentInProgress,
+			wantReason: "new reason",
+		},
+		{
+			name: "draining the old deployment",
+			deployments: []types.Deployment{
+				deployment("new", "PRIMARY", types.DeploymentRolloutStateCompleted),
+				deployment("old", "ACTIVE", types.DeploymentRolloutStateCompleted),
+			},
+			want:       deploymentInProgress,
+			wantReason: "new reason",
+		},
+		{
+			name:        "completed",
+			deployments: []types.Deployment{deployment("new", "PRIMARY", types.DeploymentRolloutStateCompleted)},
+			want:        deploymentSucceeded,
+			wantReason:  "new reason",
+		},
+		{
+			name: "failed",
+			deployments: []types.Deployment{
+				deployment("new", "PRIMARY", types.DeploymentRolloutStateFailed),
+				deployment("old", "ACTIVE", types.DeploymentRolloutStateCompleted),
+			},
+			want:       deploymentFailed,
+			wantReason: "new reason",
+		},
+		{
+			name: "rolled back",
+			deployments: []types.Deployment{
+				deployment("old", "PRIMARY", types.DeploymentRolloutStateInProgress),
+				deployment("new", "ACTIVE", types.DeploymentRolloutStateFailed),
+			},
+			want:       deploymentFailed,
+			wantReason: "new reason",
+		},
+		{
+			name:        "replaced",
+			deployments: []types.Deployment{deployment("other", "PRIMARY", types.DeploymentRolloutStateInProgress)},
+			want:        deploymentFailed,
+			wantReason:  "The deployment is no longer active",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &DeploymentPage{deploymentId: "new"}
+
+			got, reason := p.evaluate(&types.Service{Deployments: test.deployments})
+			if got != test.want || reason != test.wantReason {
+				t.Errorf("evaluate() = %d, %q, want %d, %q", got, reason, test.want, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestEvaluateTracksFirstPrimaryDeployment(t *testing.T) {
+	p := &DeploymentPage{}
+
+	p.evaluate(&types.Service{Deployments: []types.Deployment{
+		deployment("old", "ACTIVE", types.DeploymentRolloutStateCompleted),
+		deployment("new", "PRIMARY", types.DeploymentRolloutStateInProgress),
+	}})
+
+	if p.deploymentId != "new" {
+		t.Errorf("got tracked deployment %q, want new", p.deploymentId)
+	}
+}
+
+func TestCircuitBreakerStatus(t *testing.T) {
+	tests := []struct {
+		configuration *types.DeploymentConfiguration
+		want          string
+	}{
+		{nil, "disabled"},
+		{&types.DeploymentConfiguration{}, "disabled"},
+		{&types.DeploymentConfiguration{DeploymentCircuitBreaker: &types.DeploymentCircuitBreaker{Enable: true}}, "enabled, no rollback"},
+		{&types.DeploymentConfiguration{DeploymentCircuitBreaker: &types.DeploymentCircuitBreaker{Enable: true, Rollback: true}}, "enabled, rollback on failure"},
+	}
+
+	for _, test := range tests {
+		if got := circuitBreakerStatus(test.configuration); got != test.want {
+			t.Errorf("circuitBreakerStatus(%+v) = %q, want %q", test.configuration, got, test.want)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package ecs
 
 import (
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -12,41 +11,52 @@ import (
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 
+	"github.com/bsek/s9k/internal/artifacts"
 	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/commits"
 	"github.com/bsek/s9k/internal/data"
-	"github.com/bsek/s9k/internal/github"
+	"github.com/bsek/s9k/internal/history"
 	"github.com/bsek/s9k/internal/ui"
 	"github.com/bsek/s9k/internal/utils"
 )
 
 var _ ui.ContentPage = (*ServiceDetailPage)(nil)
-var region = os.Getenv("AWS_REGION")
 
 type ServiceDetailPage struct {
 	Flex        *tview.Flex
 	CurrentItem int
+	metrics     *serviceMetrics
 }
 
-func NewServiceDetailsPage(inputData *data.ServiceData, deployFunc func(version string), restartFunc func(), openActions func(task *types.Task, container data.Container)) *ServiceDetailPage {
+func NewServiceDetailsPage(inputData *data.ServiceData, deployFunc func(version string), restartFunc, rollbackFunc, scaleFunc func(), openActions func(task *types.Task, container data.Container)) *ServiceDetailPage {
 	clusterName := utils.RemoveAllBeforeLastChar("/", inputData.Service.ClusterArn)
+	metrics := newServiceMetrics(inputData.Service, clusterName)
+
+	// the metrics are part of the first item, so they are skipped when selecting a view with tab
+	overview := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(createServiceDetailsTable(inputData.Service), 6, 1, false).
+		AddItem(metrics.view, metrics.height(), 1, false)
+
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(createServiceDetailsTable(inputData.Service, clusterName), 6, 1, false).
+		AddItem(overview, 6+metrics.height(), 1, false).
 		AddItem(createServiceTaskTable(inputData, clusterName, openActions), 0, 3, false).
-		AddItem(createDeployablesTable(inputData.Service, clusterName, deployFunc), 0, 3, false)
+		AddItem(createDeployablesTable(inputData.Service, clusterName, deployFunc), 0, 3, false).
+		AddItem(createHistoryTable(inputData.Service, clusterName), 0, 2, false)
 
 	page := &ServiceDetailPage{
 		Flex:        flex,
 		CurrentItem: 1,
+		metrics:     metrics,
 	}
 
-	handler := page.createInputHandler(restartFunc)
+	handler := page.createInputHandler(restartFunc, rollbackFunc, scaleFunc)
 	flex.SetInputCapture(handler)
 	flex.SetBorder(true)
 
 	return page
 }
 
-func (s *ServiceDetailPage) createInputHandler(restartFunc func()) func(event *tcell.EventKey) *tcell.EventKey {
+func (s *ServiceDetailPage) createInputHandler(restartFunc, rollbackFunc, scaleFunc func()) func(event *tcell.EventKey) *tcell.EventKey {
 	function := func(event *tcell.EventKey) *tcell.EventKey {
 		app := ui.App
 
@@ -61,6 +71,16 @@ func (s *ServiceDetailPage) createInputHandler(restartFunc func()) func(event *t
 			if key == 'r' || key == 'R' {
 				restartFunc()
 			}
+
+			if key == 'b' || key == 'B' {
+				rollbackFunc()
+			}
+
+			if key == 'x' || key == 'X' {
+				scaleFunc()
+			}
+
+			s.metrics.selectWindow(key)
 		}
 
 		return event
@@ -69,23 +89,13 @@ func (s *ServiceDetailPage) createInputHandler(restartFunc func()) func(event *t
 	return function
 }
 
-func createServiceDetailsTable(service *types.Service, clusterName string) *tview.Table {
+func createServiceDetailsTable(service *types.Service) *tview.Table {
 	detailsTable := tview.NewTable()
 
 	detailsTable.
 		SetBorder(true).
 		SetTitle(fmt.Sprintf(" 📋 %s details ", *service.ServiceName))
 
-	// fetch metrics
-	meterWidth := 10
-	cpuMeter := ""
-	memoryMeter := ""
-	cpuUsed, cpuReserved, memUsed, memReserved, err := aws.FetchCpuAndMemoryUsage(*service.ServiceName, clusterName)
-	if err == nil {
-		cpuMeter = utils.BuildAsciiMeterCurrentTotal(cpuUsed, cpuReserved, meterWidth)
-		memoryMeter = utils.BuildAsciiMeterCurrentTotal(memUsed, memReserved, meterWidth)
-	}
-
 	tableData := [][]string{}
 
 	deployTimeTxt := "n/a"
@@ -107,13 +117,11 @@ func createServiceDetailsTable(service *types.Service, clusterName string) *tvie
 		utils.LowerTitle(*service.Status),
 		deployTimeTxt,
 		taskCount,
-		cpuMeter,
-		memoryMeter,
 	})
 
-	headers := []string{"Name", "Task Definition", "Status", "Deployed", "Tasks", "Cpu usage", "Memory usage"}
-	alignments := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignRight, tview.AlignRight}
-	expansions := []int{1, 1, 1, 1, 1, 1, 1}
+	headers := []string{"Name", "Task Definition", "Status", "Deployed", "Tasks"}
+	alignments := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{1, 1, 1, 1, 1}
 
 	ui.AddTableData(detailsTable, headers, tableData, alignments, expansions, tcell.ColorGreenYellow, true)
 
@@ -210,20 +218,27 @@ func createDeployablesTable(service *types.Service, clusterName string, deployFu
 
 	deployTable.SetSelectable(true, false)
 
+	source, err := artifacts.ForService(clusterName, *service.ServiceName)
+	if err != nil {
+		log.Error().Err(err).Msgf("No artifact source for service %s", *service.ServiceName)
+	} else {
+		deployTable.SetTitle(fmt.Sprintf(" 📦 Deployables (%s) ", source.Name()))
+	}
+
 	var wg sync.WaitGroup
 
-	var commits []github.Commit
-	var packages []aws.Package
+	var commitList []commits.Commit
+	var packages []artifacts.Artifact
 
 	wg.Add(2)
 	go func() {
-		c := fetchCommits(*service.ServiceName)
-		commits = append(commits, c...)
+		c := fetchCommits(clusterName, *service.ServiceName)
+		commitList = append(commitList, c...)
 		wg.Done()
 	}()
 
 	go func() {
-		p := fetchPackages(clusterName, *service.ServiceName)
+		p := fetchPackages(source)
 		packages = append(packages, p...)
 		wg.Done()
 	}()
@@ -232,21 +247,17 @@ func createDeployablesTable(service *types.Service, clusterName string, deployFu
 
 	data := [][]string{{}}
 
-	sort.SliceStable(packages, func(i, j int) bool {
-		return packages[i].Created.After(packages[j].Created)
-	})
-
 	for _, p := range packages {
 		msg := ""
-		for _, c := range commits {
-			if p.Sha[4:] == c.Sha {
+		for _, c := range commitList {
+			if p.Commit != "" && strings.HasPrefix(c.Sha, p.Commit) {
 				msg = c.Message
 			}
 		}
 
 		data = append(data, []string{
 			utils.FormatLocalDateTime(p.Created),
-			p.Sha,
+			p.Version,
 			msg,
 		})
 	}
@@ -257,32 +268,71 @@ func createDeployablesTable(service *types.Service, clusterName string, deployFu
 
 	ui.AddTableData(deployTable, headers, data, alignment, expansions, tcell.ColorBlue, true)
 
+	// set reference to artifact
+	for i := 1; i < len(packages)+1; i++ {
+		deployTable.GetCell(i, 1).SetReference(packages[i-1])
+	}
+
 	deployTable.SetSelectedFunc(func(row, _ int) {
-		version := deployTable.GetCell(row, 1).Text
-		for _, v := range packages {
-			if v.Sha == version {
-				deployFunc(fmt.Sprintf("%s.dkr.ecr.%s.amazonaws.com/%s:%s", v.RegistryID, region, v.RepositoryName, version))
-			}
+		artifact, found := deployTable.GetCell(row, 1).Reference.(artifacts.Artifact)
+		if found {
+			deployFunc(artifact.Image)
 		}
 	})
 
 	return deployTable
 }
 
-func fetchPackages(cluster, name string) []aws.Package {
-	ecrRepo := fmt.Sprintf("%s-%s", cluster, name)
-	p, err := aws.FetchPackagesFromECR(ecrRepo)
+func createHistoryTable(service *types.Service, clusterName string) *tview.Table {
+	historyTable := tview.NewTable().SetSelectable(true, false)
+
+	historyTable.
+		SetBorder(true).
+		SetTitle(" 🕘 Deployment history ")
+
+	data := [][]string{}
+	for _, item := range history.ForService(clusterName, *service) {
+		data = append(data, []string{
+			utils.FormatLocalDateTime(item.Updated),
+			item.Version,
+			item.Revision,
+			item.User,
+			item.Source,
+		})
+	}
+
+	headers := []string{"Deployed", "Version", "Revision", "User", "Source"}
+	expansions := []int{1, 2, 1, 2, 1}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft}
+
+	ui.AddTableData(historyTable, headers, data, alignment, expansions, tcell.ColorLightGray, true)
+
+	return historyTable
+}
+
+func fetchPackages(source artifacts.Source) []artifacts.Artifact {
+	if source == nil {
+		return nil
+	}
+
+	p, err := source.List()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to read packages from ecr")
+		log.Error().Err(err).Msgf("Failed to read packages from %s", source.Name())
 	}
 
 	return p
 }
 
-func fetchCommits(name string) []github.Commit {
-	c, err := github.FetchCommits("fasit-" + name)
+func fetchCommits(cluster, name string) []commits.Commit {
+	provider, err := commits.ForService(cluster, name)
+	if err != nil {
+		log.Error().Err(err).Msg("No commit provider")
+		return nil
+	}
+
+	c, err := provider.List()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to read commits from github")
+		log.Error().Err(err).Msgf("Failed to read commits from %s", provider.Name())
 	}
 
 	log.Debug().Msgf("Found commits: (%v)", c)
@@ -324,6 +374,9 @@ func (*ServiceDetailPage) ContextView() tview.Primitive {
 	fmt.Fprintln(bw, "[white::b]Tab [darkcyan::-]Select view")
 	fmt.Fprintln(bw, "")
 	fmt.Fprintln(bw, "[white::b]r [darkcyan::-]Restart service")
+	fmt.Fprintln(bw, "[white::b]b [darkcyan::-]Roll back service")
+	fmt.Fprintln(bw, "[white::b]x [darkcyan::-]Scale service")
+	fmt.Fprintln(bw, "[white::b]1-3 [darkcyan::-]Metrics time range")
 	fmt.Fprintln(bw, "")
 	fmt.Fprintln(bw, "[white::b]Enter [darkcyan::-]Select")
 
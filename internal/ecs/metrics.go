@@ -0,0 +1,75 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+// serviceMetrics is the metrics pane of the service details, with the utilization and tasks of the service and the
+// traffic of its load balancer
+type serviceMetrics struct {
+	view            *ui.MetricsView
+	clusterName     string
+	serviceName     string
+	targetGroupArns []string
+	window          ui.MetricWindow
+}
+
+func newServiceMetrics(service *types.Service, clusterName string) *serviceMetrics {
+	view := ui.NewMetricsView(3).
+		SetColor("5xx responses", tcell.ColorRed)
+	view.SetBorder(true)
+
+	targetGroupArns := []string{}
+	for _, loadBalancer := range service.LoadBalancers {
+		if loadBalancer.TargetGroupArn != nil {
+			targetGroupArns = append(targetGroupArns, *loadBalancer.TargetGroupArn)
+		}
+	}
+
+	metrics := &serviceMetrics{
+		view:            view,
+		clusterName:     clusterName,
+		serviceName:     *service.ServiceName,
+		targetGroupArns: targetGroupArns,
+		window:          ui.MetricWindows[0],
+	}
+	metrics.load()
+
+	return metrics
+}
+
+// height returns the lines needed for the pane, a second row of sparklines is shown for the load balancer
+func (m *serviceMetrics) height() int {
+	if len(m.targetGroupArns) > 0 {
+		return 10
+	}
+	return 6
+}
+
+// selectWindow reloads the metrics if the key selects another time window
+func (m *serviceMetrics) selectWindow(key rune) {
+	if window, found := ui.MetricWindowForKey(key); found && window != m.window {
+		m.window = window
+		m.load()
+	}
+}
+
+// load reads the metrics for the selected window. Errors are shown in the title
+func (m *serviceMetrics) load() {
+	series, err := aws.FetchServiceMetrics(m.clusterName, m.serviceName, m.targetGroupArns, m.window.Duration)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read metrics of service %s", m.serviceName)
+		m.view.SetTitle(" 📈 Metrics (failed to read, check log file) ")
+		return
+	}
+
+	m.view.SetTitle(fmt.Sprintf(" 📈 Metrics, %s ", m.window.Label))
+	m.view.SetSeries(series)
+}
@@ -48,16 +48,34 @@ func NewServicesPage() *ServicePage {
 			restart(*service.Service.ServiceName, clusterName)
 		}
 
+		rollbackFunction := func() {
+			rollback(clusterName, *service.Service.ServiceName)
+		}
+
+		scaleFunction := func() {
+			scale(clusterName, *service.Service.ServiceName)
+		}
+
 		actionsFunc := func(task *types.Task, container data.Container) {
 			action(*task.TaskArn, *service.Service.ClusterArn, container)
 		}
 
-		detailsPage := NewServiceDetailsPage(&service, deployFunction, restartFunction, actionsFunc)
+		detailsPage := NewServiceDetailsPage(&service, deployFunction, restartFunction, rollbackFunction, scaleFunction, actionsFunc)
 
 		ui.App.RegisterContent(detailsPage)
 		ui.App.ShowPage(detailsPage)
 	})
 
+	servicesTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && (event.Rune() == 'x' || event.Rune() == 'X') {
+			row, _ := servicesTable.GetSelection()
+			if service, found := servicesTable.GetCell(row, 1).Reference.(data.ServiceData); found {
+				scale(utils.RemoveAllBeforeLastChar("/", service.Service.ClusterArn), *service.Service.ServiceName)
+			}
+		}
+		return event
+	})
+
 	return &ServicePage{
 		name:          "services",
 		servicesTable: servicesTable,
@@ -70,6 +88,7 @@ func createHelpText() *tview.TextView {
 	tw.SetDynamicColors(true).SetWrap(false)
 
 	fmt.Fprintln(tw, "[::b]Enter [darkcyan::-]action")
+	fmt.Fprintln(tw, "[::b]x [darkcyan::-]scale")
 
 	return tw
 }
@@ -91,9 +110,11 @@ func (p *ServicePage) HandleInputCapture(event *tcell.EventKey) *tcell.EventKey
 }
 
 func (p *ServicePage) Render(accountData *data.AccountData) {
+	p.servicesTable.Clear()
+
 	clusterData := accountData.ClusterData
 
-	if len(clusterData.Services) == 0 {
+	if clusterData == nil || len(clusterData.Services) == 0 {
 		return
 	}
 
@@ -0,0 +1,155 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/park"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+var _ ui.ContentPage = (*ParkPage)(nil)
+
+// ParkPage shows the progress of parking or unparking the services in a cluster, one row per service
+type ParkPage struct {
+	resultsTable *tview.Table
+	clusterName  string
+	action       string
+	results      []park.Result
+	done         bool
+}
+
+// confirmPark asks for confirmation and parks every service in the cluster
+func confirmPark(clusterName string) {
+	ui.CreateConfirmBox(fmt.Sprintf("Do you want to park cluster %s? The desired count of every service is saved and the services are scaled to 0.", clusterName), func() {
+		showParkPage(clusterName, "Parking", park.Park)
+	}, func() {})
+}
+
+// confirmUnpark asks for confirmation and restores the saved desired counts of the services in the cluster
+func confirmUnpark(clusterName string) {
+	ui.CreateConfirmBox(fmt.Sprintf("Do you want to unpark cluster %s? Parked services are scaled back to their saved desired count.", clusterName), func() {
+		showParkPage(clusterName, "Unparking", park.Unpark)
+	}, func() {})
+}
+
+func showParkPage(clusterName, action string, run func(clusterName string, progress func(park.Result)) error) {
+	page := NewParkPage(clusterName, action)
+	ui.App.RegisterContent(page)
+	ui.App.ShowPage(page)
+
+	go func() {
+		err := run(clusterName, func(result park.Result) {
+			ui.App.TviewApp.QueueUpdateDraw(func() {
+				page.add(result)
+			})
+		})
+
+		ui.App.TviewApp.QueueUpdateDraw(func() {
+			page.finish(err)
+		})
+	}()
+}
+
+// NewParkPage returns a page for the results of parking or unparking the cluster
+func NewParkPage(clusterName, action string) *ParkPage {
+	resultsTable := tview.NewTable().SetSelectable(true, false)
+	resultsTable.SetBorder(true)
+
+	page := &ParkPage{
+		resultsTable: resultsTable,
+		clusterName:  clusterName,
+		action:       action,
+	}
+	page.render()
+
+	return page
+}
+
+func (p *ParkPage) add(result park.Result) {
+	p.results = append(p.results, result)
+	p.render()
+}
+
+func (p *ParkPage) finish(err error) {
+	p.done = true
+	p.render()
+
+	if err != nil {
+		log.Error().Err(err).Msgf("%s cluster %s failed", p.action, p.clusterName)
+		ui.CreateMessageBox(fmt.Sprintf("%s cluster %s failed, check log file", p.action, p.clusterName))
+	}
+}
+
+func (p *ParkPage) render() {
+	p.resultsTable.Clear()
+
+	state := "in progress"
+	if p.done {
+		state = "done"
+	}
+	p.resultsTable.SetTitle(fmt.Sprintf(" 🅿 %s %s (%d services, %s) ", p.action, p.clusterName, len(p.results), state))
+
+	tableData := [][]string{}
+	for _, r := range p.results {
+		tableData = append(tableData, []string{
+			r.Service,
+			utils.I32ToString(r.Before),
+			utils.I32ToString(r.After),
+			r.Status,
+			r.Message,
+		})
+	}
+
+	headers := []string{"Service", "Desired before", "Desired after", "Status", "Message"}
+	alignment := []int{tview.AlignLeft, tview.AlignRight, tview.AlignRight, tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{2, 1, 1, 1, 4}
+
+	ui.AddTableData(p.resultsTable, headers, tableData, alignment, expansions, tcell.ColorWhite, true)
+
+	// failed services are highlighted
+	for row, r := range p.results {
+		if r.Status == park.StatusFailed {
+			p.resultsTable.GetCell(row+1, 3).SetTextColor(tcell.ColorRed)
+		}
+	}
+}
+
+func (*ParkPage) Name() string {
+	return "park progress"
+}
+
+func (*ParkPage) Render(accountData *data.AccountData) {
+}
+
+func (p *ParkPage) View() tview.Primitive {
+	return p.resultsTable
+}
+
+func (*ParkPage) Close() {
+}
+
+func (*ParkPage) IsPersistent() bool {
+	return false
+}
+
+func (p *ParkPage) SetFocus(app *tview.Application) {
+	app.SetFocus(p.resultsTable)
+}
+
+func (*ParkPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	fmt.Fprintln(tw, "[white::b]c [darkcyan::-]Clusters")
+	fmt.Fprintln(tw, "[white::b]s [darkcyan::-]Services")
+
+	return tw
+}
@@ -0,0 +1,156 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/config"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// maxRollbackRevisions is the number of task definition revisions listed in the rollback dialog
+const maxRollbackRevisions = 10
+
+// rollback lists the previous revisions of the service's task definition family and points the service back at
+// the selected revision
+func rollback(clusterName, serviceName string) {
+	const ROLLBACK_DIALOG = "rollback_dialog"
+	pages := ui.App.Content
+
+	// the service is described again, the task definition may have changed since the page was opened
+	service, err := aws.DescribeService(clusterName, serviceName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to describe service %s", serviceName)
+		ui.CreateMessageBox("Failed to read service, check log file")
+		return
+	}
+
+	family := taskDefinitionFamily(service.TaskDefinition)
+
+	revisions, err := aws.ListTaskDefinitionRevisions(family, maxRollbackRevisions)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list revisions of task definition %s", family)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to list revisions of task definition %s, check log file", family))
+		return
+	}
+
+	revisionsTable := createRevisionsTable(revisions, awssdk.ToString(service.TaskDefinition))
+	revisionsTable.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" ⏪ Roll back %s to revision of %s ", serviceName, family))
+
+	revisionsTable.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			pages.RemovePage(ROLLBACK_DIALOG)
+		}
+	})
+
+	revisionsTable.SetSelectedFunc(func(row, _ int) {
+		taskDefinition, found := revisionsTable.GetCell(row, 0).Reference.(types.TaskDefinition)
+		if !found {
+			return
+		}
+
+		if awssdk.ToString(taskDefinition.TaskDefinitionArn) == awssdk.ToString(service.TaskDefinition) {
+			ui.CreateMessageBox(fmt.Sprintf("%s is already running revision %d", serviceName, taskDefinition.Revision))
+			return
+		}
+
+		image := aws.ServiceImage(taskDefinition, serviceName)
+		text := fmt.Sprintf("Do you want to roll back %s from %s to %s (%s)?",
+			serviceName, aws.ShortenTaskDefArn(service.TaskDefinition), aws.ShortenTaskDefArn(taskDefinition.TaskDefinitionArn),
+			utils.RemoveAllBeforeLastChar("/", &image))
+
+		pages.RemovePage(ROLLBACK_DIALOG)
+		ui.CreateConfirmBox(text, func() {
+			doRollback(clusterName, serviceName, taskDefinition)
+		}, func() {})
+	})
+
+	modalPage := ui.CreateModalPage(revisionsTable, nil, 120, maxRollbackRevisions+4, ROLLBACK_DIALOG)
+
+	pages.AddPage(ROLLBACK_DIALOG, modalPage, true, true)
+}
+
+func doRollback(clusterName, serviceName string, taskDefinition types.TaskDefinition) {
+	revision := aws.ShortenTaskDefArn(taskDefinition.TaskDefinitionArn)
+	log.Debug().Msgf("Rolling back service [%s] to [%s]", serviceName, revision)
+
+	err := aws.RollbackECSService(serviceName, clusterName, taskDefinition)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to roll back service %s to %s", serviceName, revision)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to roll back %s to %s, check log file", serviceName, revision))
+		return
+	}
+
+	// follow the rollout on the deployment page
+	page := NewDeploymentPage(clusterName, serviceName, revision)
+	ui.App.RegisterContent(page)
+	ui.App.ShowPage(page)
+
+	// the service is rolled back even if the parameter can not be updated, the parameter matches the running
+	// image as after a deployment
+	parameter := config.ForService(clusterName, serviceName).ImageTagParameterName()
+	image := aws.ServiceImage(taskDefinition, serviceName)
+	if image == "" {
+		log.Error().Msgf("No container image found in task definition %s", revision)
+		ui.CreateMessageBox(fmt.Sprintf("%s was rolled back to %s, but no image was found to store in %s", serviceName, revision, parameter))
+		return
+	}
+
+	if err := aws.PutImageTag(image, parameter); err != nil {
+		log.Error().Err(err).Msgf("Failed to update image tag parameter %s", parameter)
+		ui.CreateMessageBox(fmt.Sprintf("%s was rolled back to %s, but failed to update %s, check log file", serviceName, revision, parameter))
+	}
+}
+
+func createRevisionsTable(revisions []types.TaskDefinition, current string) *tview.Table {
+	table := tview.NewTable().SetSelectable(true, false)
+
+	tableData := [][]string{}
+	for _, r := range revisions {
+		revision := utils.I32ToString(r.Revision)
+		if awssdk.ToString(r.TaskDefinitionArn) == current {
+			revision = fmt.Sprintf("%s (current)", revision)
+		}
+
+		images := lo.Map(r.ContainerDefinitions, func(c types.ContainerDefinition, _ int) string {
+			return utils.RemoveAllBeforeLastChar("/", c.Image)
+		})
+
+		tableData = append(tableData, []string{
+			revision,
+			formatTime(r.RegisteredAt),
+			strings.Join(images, ", "),
+		})
+	}
+
+	headers := []string{"Revision", "Registered", "Images"}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{1, 1, 4}
+
+	ui.AddTableData(table, headers, tableData, alignment, expansions, tcell.ColorLightBlue, true)
+
+	// set reference to task definition
+	for i, r := range revisions {
+		table.GetCell(i+1, 0).SetReference(r)
+	}
+
+	return table
+}
+
+// taskDefinitionFamily returns the family of a task definition arn, e.g. family for .../task-definition/family:3
+func taskDefinitionFamily(taskDefinitionArn *string) string {
+	name := utils.RemoveAllBeforeLastChar("/", taskDefinitionArn)
+	family, _, _ := strings.Cut(name, ":")
+	return family
+}
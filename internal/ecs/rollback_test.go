@@ -0,0 +1,21 @@
+package ecs
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestTaskDefinitionFamily(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:ecs:eu-north-1:123456789012:task-definition/dev-api:3": "dev-api",
+		"dev-api:12": "dev-api",
+		"dev-api":    "dev-api",
+	}
+
+	for arn, want := range tests {
+		if got := taskDefinitionFamily(awssdk.String(arn)); got != want {
+			t.Errorf("taskDefinitionFamily(%s) = %s, want %s", arn, got, want)
+		}
+	}
+}
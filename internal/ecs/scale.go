@@ -0,0 +1,103 @@
+package ecs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+// scale opens a dialog to change the desired number of tasks of the service. The new count is validated against
+// the Application Auto Scaling limits of the service and confirmed before the service is updated
+func scale(clusterName, serviceName string) {
+	const SCALE_DIALOG = "scale_dialog"
+	pages := ui.App.Content
+
+	service, err := aws.DescribeService(clusterName, serviceName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to describe service %s", serviceName)
+		ui.CreateMessageBox("Failed to read service, check log file")
+		return
+	}
+
+	limits, err := aws.FetchServiceScalingLimits(clusterName, serviceName)
+	if err != nil {
+		ui.CreateMessageBox("Failed to read auto scaling limits of the service, check log file")
+		return
+	}
+
+	autoScaling := "not registered"
+	if limits != nil {
+		autoScaling = fmt.Sprintf("min %d, max %d", limits.Min, limits.Max)
+	}
+
+	current := service.DesiredCount
+
+	form := tview.NewForm().
+		AddTextView("Tasks", fmt.Sprintf("%d running, %d pending, %d desired", service.RunningCount, service.PendingCount, current), 0, 1, false, false).
+		AddTextView("Auto scaling", autoScaling, 0, 1, false, false).
+		AddInputField("Desired count", strconv.Itoa(int(current)), 10, tview.InputFieldInteger, nil)
+
+	input := form.GetFormItemByLabel("Desired count").(*tview.InputField)
+
+	form.
+		AddButton("Scale", func() {
+			desired, err := parseDesiredCount(input.GetText(), limits)
+			if err != nil {
+				ui.CreateMessageBox(err.Error())
+				return
+			}
+
+			if desired == current {
+				ui.CreateMessageBox(fmt.Sprintf("%s already has %d desired tasks", serviceName, current))
+				return
+			}
+
+			pages.RemovePage(SCALE_DIALOG)
+			ui.CreateConfirmBox(fmt.Sprintf("Do you want to scale %s from %d to %d tasks?", serviceName, current, desired), func() {
+				doScale(clusterName, serviceName, current, desired)
+			}, func() {})
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(SCALE_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(SCALE_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" Scale %s ", serviceName)).SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 60, 11, SCALE_DIALOG)
+
+	pages.AddPage(SCALE_DIALOG, modalPage, true, true)
+}
+
+// parseDesiredCount parses the desired count and checks it against the auto scaling limits, if any
+func parseDesiredCount(text string, limits *aws.ScalingLimits) (int32, error) {
+	desired, err := strconv.ParseInt(text, 10, 32)
+	if err != nil || desired < 0 {
+		return 0, fmt.Errorf("%q is not a valid number of tasks", text)
+	}
+
+	if limits != nil && !limits.Allows(int32(desired)) {
+		return 0, fmt.Errorf("%d is outside the auto scaling limits of the service (min %d, max %d)", desired, limits.Min, limits.Max)
+	}
+
+	return int32(desired), nil
+}
+
+func doScale(clusterName, serviceName string, current, desired int32) {
+	log.Debug().Msgf("Scaling service [%s] from %d to %d tasks", serviceName, current, desired)
+
+	err := aws.ScaleECSService(clusterName, serviceName, desired)
+	if err != nil {
+		ui.CreateMessageBox(fmt.Sprintf("Failed to scale %s, check log file", serviceName))
+		return
+	}
+
+	ui.CreateMessageBox(fmt.Sprintf("%s scaled from %d to %d tasks", serviceName, current, desired))
+}
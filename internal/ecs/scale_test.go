@@ -0,0 +1,43 @@
+package ecs
+
+import (
+	"testing"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func TestParseDesiredCount(t *testing.T) {
+	limits := &aws.ScalingLimits{Min: 1, Max: 4}
+
+	tests := []struct {
+		text    string
+		limits  *aws.ScalingLimits
+		want    int32
+		wantErr bool
+	}{
+		{text: "3", want: 3},
+		{text: "0", want: 0},
+		{text: "12", want: 12},
+		{text: "4", limits: limits, want: 4},
+		{text: "1", limits: limits, want: 1},
+		{text: "0", limits: limits, wantErr: true},
+		{text: "5", limits: limits, wantErr: true},
+		{text: "-1", wantErr: true},
+		{text: "two", wantErr: true},
+		{text: "", wantErr: true},
+		{text: "3000000000", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := parseDesiredCount(test.text, test.limits)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("parseDesiredCount(%q, %v) = %d, want an error", test.text, test.limits, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("parseDesiredCount(%q, %v) = %d, %v, want %d", test.text, test.limits, got, err, test.want)
+		}
+	}
+}
@@ -9,11 +9,14 @@ import (
 	"github.com/bsek/s9k/internal/aws"
 	"github.com/bsek/s9k/internal/data"
 	"github.com/bsek/s9k/internal/ecs"
+	"github.com/bsek/s9k/internal/environment"
 	"github.com/bsek/s9k/internal/lambda"
 	"github.com/bsek/s9k/internal/ui"
 )
 
-func LoadData() *data.AccountData {
+// LoadData reads the account information and the data for the given ECS cluster. If no cluster name is
+// provided, the first cluster found in the account is used
+func LoadData(clusterName string) *data.AccountData {
 	account, _, err := aws.GetAccountInformation()
 	if err != nil {
 		fmt.Println("Failed to read account information, make sure you are logged in to AWS")
@@ -26,32 +29,66 @@ func LoadData() *data.AccountData {
 		log.Fatal().Err(err).Msg("Failed to read ecs clusters or no clusters found")
 	}
 
-	clusterName := clusters[0].ClusterName
-	accountData := data.NewAccountData(*clusterName, *account)
-	accountData.Refresh()
+	cluster := clusters[0]
+	if clusterName != "" {
+		found := false
+		for _, v := range clusters {
+			if *v.ClusterName == clusterName {
+				cluster = v
+				found = true
+			}
+		}
+
+		if !found {
+			fmt.Printf("Could not find an ECS cluster named %s\n", clusterName)
+			log.Fatal().Msgf("Could not find an ECS cluster named %s", clusterName)
+		}
+	}
+
+	accountData := data.NewAccountData(*cluster.ClusterName, *account)
+	if err := accountData.Refresh(); err != nil {
+		fmt.Println("Failed to read account data")
+		log.Fatal().Err(err).Msg("Failed to read account data")
+	}
 
 	return accountData
 }
 
-// Entrypoint for the application
-func Entrypoint() {
+// Entrypoint for the application. If clusterName is empty and the account has more than one ECS
+// cluster, the application starts on the cluster selection page
+func Entrypoint(clusterName string) {
 	fmt.Println("Loading information...")
 
-	accountData := LoadData()
+	accountData := LoadData(clusterName)
 
 	ui.App = ui.NewApplication(accountData)
 
 	servicesPage := ecs.NewServicesPage()
+	clustersPage := ecs.NewClustersPage(func(clusterName string) {
+		if err := ui.App.SwitchCluster(clusterName); err != nil {
+			log.Error().Err(err).Msgf("Failed to switch to cluster %s", clusterName)
+			ui.CreateMessageBox(fmt.Sprintf("Failed to read cluster %s, check log file", clusterName))
+			return
+		}
+		ui.App.ShowPage(servicesPage)
+	})
 	lambdasPage := lambda.NewLambdasPage()
 	apigatewayPage := apigateway.NewApiGatewayPage()
+	environmentPage := environment.NewEnvironmentPage()
 
 	ui.App.BuildApplicationUI()
 
 	ui.App.RegisterContent(servicesPage)
+	ui.App.RegisterContent(clustersPage)
 	ui.App.RegisterContent(lambdasPage)
 	ui.App.RegisterContent(apigatewayPage)
+	ui.App.RegisterContent(environmentPage)
 
-	ui.App.ShowPage(servicesPage)
+	if clusterName == "" && len(accountData.Clusters) > 1 {
+		ui.App.ShowPage(clustersPage)
+	} else {
+		ui.App.ShowPage(servicesPage)
+	}
 
 	if err := ui.App.Run(); err != nil {
 		fmt.Println("Failed to start application")
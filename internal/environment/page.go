@@ -0,0 +1,202 @@
+package environment
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+var _ ui.ContentPage = (*EnvironmentPage)(nil)
+
+// EnvironmentPage lets the user switch the AWS profile and region used by the application
+type EnvironmentPage struct {
+	Flex          *tview.Flex
+	profilesTable *tview.Table
+	regionsTable  *tview.Table
+	CurrentItem   int
+}
+
+func NewEnvironmentPage() *EnvironmentPage {
+	profilesTable := tview.NewTable().SetSelectable(true, false)
+	profilesTable.
+		SetBorder(true).
+		SetTitle(" 👤 Profiles ")
+
+	regionsTable := tview.NewTable().SetSelectable(true, false)
+	regionsTable.
+		SetBorder(true).
+		SetTitle(" 🌍 Regions ")
+
+	profilesTable.SetSelectedFunc(func(row, _ int) {
+		profile, found := profilesTable.GetCell(row, 0).Reference.(string)
+		if found {
+			// the region is kept, it may have been picked before the profile
+			switchContext(profile, aws.CurrentRegion())
+		}
+	})
+
+	regionsTable.SetSelectedFunc(func(row, _ int) {
+		region, found := regionsTable.GetCell(row, 0).Reference.(string)
+		if found {
+			switchContext(aws.CurrentProfile(), region)
+		}
+	})
+
+	flex := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(profilesTable, 0, 1, true).
+		AddItem(regionsTable, 0, 1, false)
+
+	page := &EnvironmentPage{
+		Flex:          flex,
+		profilesTable: profilesTable,
+		regionsTable:  regionsTable,
+		CurrentItem:   0,
+	}
+
+	flex.SetInputCapture(page.inputHandler)
+
+	return page
+}
+
+func (e *EnvironmentPage) inputHandler(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyTab {
+		e.CurrentItem = (e.CurrentItem + 1) % e.Flex.GetItemCount()
+		ui.App.TviewApp.SetFocus(e.Flex.GetItem(e.CurrentItem))
+		return nil
+	}
+
+	return event
+}
+
+// switchContext reconfigures the AWS clients for the given profile and region, reads the account information
+// and reloads all data for the first ECS cluster in the account. If the new profile or region can not be used,
+// the previous configuration is restored
+func switchContext(profile, region string) {
+	previousProfile := aws.CurrentProfile()
+	previousRegion := aws.CurrentRegion()
+
+	restore := func() {
+		if err := aws.Configure(previousProfile, previousRegion); err != nil {
+			log.Error().Err(err).Msg("Failed to restore previous AWS configuration")
+		}
+	}
+
+	err := aws.Configure(profile, region)
+
+	var account *string
+	if err == nil {
+		account, _, err = aws.GetAccountInformation()
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to switch to profile [%s] and region [%s]", profile, region)
+		restore()
+
+		ui.CreateMessageBox(fmt.Sprintf("Failed to switch to profile %s, make sure you are logged in. See log for more information.", profile))
+		return
+	}
+
+	log.Info().Msgf("Switched to profile [%s] and region [%s]", aws.CurrentProfile(), aws.CurrentRegion())
+
+	clusterName := ""
+	clusters, err := aws.ListECSClusters()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read ecs clusters")
+	} else if len(clusters) > 0 {
+		clusterName = *clusters[0].ClusterName
+	}
+
+	// without clusters in the region, no services are read and only the other data is shown
+	accountData := data.NewAccountData(clusterName, *account)
+	if err := accountData.Refresh(); err != nil {
+		log.Error().Err(err).Msgf("Failed to read data with profile [%s] and region [%s]", profile, region)
+		restore()
+
+		ui.CreateMessageBox(fmt.Sprintf("Failed to read data with profile %s in %s, see log for more information.", profile, region))
+		return
+	}
+
+	ui.App.SetAccountData(accountData)
+}
+
+func (e *EnvironmentPage) Render(accountData *data.AccountData) {
+	e.profilesTable.Clear()
+	e.regionsTable.Clear()
+
+	profiles, err := aws.ListProfiles()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read profiles from the shared config files")
+	}
+
+	profileData := lo.Map(profiles, func(profile string, _ int) []string {
+		return []string{profile, currentMarker(profile == accountData.Profile)}
+	})
+
+	regionData := lo.Map(aws.Regions, func(region string, _ int) []string {
+		return []string{region, currentMarker(region == accountData.Region)}
+	})
+
+	alignment := []int{tview.AlignLeft, tview.AlignCenter}
+	expansions := []int{3, 1}
+
+	ui.AddTableData(e.profilesTable, []string{"Profile ▾", "Current"}, profileData, alignment, expansions, tcell.ColorWhite, true)
+	ui.AddTableData(e.regionsTable, []string{"Region", "Current"}, regionData, alignment, expansions, tcell.ColorWhite, true)
+
+	// set references to profile and region names
+	for i := 1; i < len(profiles)+1; i++ {
+		e.profilesTable.GetCell(i, 0).SetReference(profiles[i-1])
+	}
+
+	for i := 1; i < len(aws.Regions)+1; i++ {
+		e.regionsTable.GetCell(i, 0).SetReference(aws.Regions[i-1])
+	}
+}
+
+func currentMarker(current bool) string {
+	if current {
+		return "*"
+	}
+	return ""
+}
+
+func (e *EnvironmentPage) Name() string {
+	return "environment"
+}
+
+func (e *EnvironmentPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	bw := tw.BatchWriter()
+	defer bw.Close()
+
+	fmt.Fprintln(bw, "[white::b]Tab [darkcyan::-]Select view")
+	fmt.Fprintln(bw, "")
+	fmt.Fprintln(bw, "[white::b]Enter [darkcyan::-]Switch to profile or region")
+
+	return tw
+}
+
+func (e *EnvironmentPage) SetFocus(app *tview.Application) {
+	app.SetFocus(e.Flex.GetItem(e.CurrentItem))
+}
+
+func (e *EnvironmentPage) View() tview.Primitive {
+	return e.Flex
+}
+
+func (e *EnvironmentPage) IsPersistent() bool {
+	return true
+}
+
+func (e *EnvironmentPage) Close() {
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,16 +10,21 @@ import (
 	"github.com/google/go-github/v50/github"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
+
+	"github.com/bsek/s9k/internal/config"
 )
 
 var client *github.Client
 
+var errNoClient = errors.New("github client is not configured")
+
 type Commit struct {
 	Sha     string
 	Message string
 }
 
 type Package struct {
+	Tag     string
 	Sha     string
 	Image   string
 	Created time.Time
@@ -34,7 +40,12 @@ func CreateClient(token string) {
 	client = github.NewClient(tc)
 }
 
-func FetchPackagesfromGhcr(name string) ([]Package, error) {
+// FetchPackagesfromGhcr reads the latest container package versions owned by the given organisation
+func FetchPackagesfromGhcr(owner, name string) ([]Package, error) {
+	if client == nil {
+		return nil, errNoClient
+	}
+
 	opts := github.PackageListOptions{
 		PackageType: github.String("container"),
 		ListOptions: github.ListOptions{
@@ -42,7 +53,7 @@ func FetchPackagesfromGhcr(name string) ([]Package, error) {
 		},
 	}
 
-	versions, _, err := client.Organizations.PackageGetAllVersions(context.Background(), "oslokommune", "container", name, &opts)
+	versions, _, err := client.Organizations.PackageGetAllVersions(context.Background(), owner, "container", name, &opts)
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to read packages from repo %s", name)
 		return nil, err
@@ -54,11 +65,15 @@ func FetchPackagesfromGhcr(name string) ([]Package, error) {
 		if len(v.Metadata.Container.Tags) > 0 {
 			tag = v.Metadata.Container.Tags[0]
 
-			shortSha := tag[len(tag)-7:]
+			shortSha := tag
+			if len(tag) > 7 {
+				shortSha = tag[len(tag)-7:]
+			}
 
 			list = append(list, Package{
+				Tag:     tag,
 				Sha:     shortSha,
-				Image:   fmt.Sprintf("ghcr.io/oslokommune/%s:%s", name, tag),
+				Image:   fmt.Sprintf("ghcr.io/%s/%s:%s", owner, name, tag),
 				Created: v.CreatedAt.Time,
 			})
 		}
@@ -67,12 +82,17 @@ func FetchPackagesfromGhcr(name string) ([]Package, error) {
 	return list, nil
 }
 
-func FetchCommits(name string) ([]Commit, error) {
+// FetchCommits reads the latest commits on the given branch of a repository
+func FetchCommits(owner, name, branch string) ([]Commit, error) {
+	if client == nil {
+		return nil, errNoClient
+	}
+
 	opts := github.CommitsListOptions{
-		SHA:         "main",
+		SHA:         branch,
 		ListOptions: github.ListOptions{PerPage: 20},
 	}
-	commits, _, err := client.Repositories.ListCommits(context.Background(), "oslohel", name, &opts)
+	commits, _, err := client.Repositories.ListCommits(context.Background(), owner, name, &opts)
 
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to read commits from repo %s", name)
@@ -96,19 +116,25 @@ func FetchCommits(name string) ([]Commit, error) {
 	return list, nil
 }
 
+// CallGithubAction dispatches the workflow configured for the service to update its container image version
 func CallGithubAction(clusterName, serviceName, version string) error {
-	owner := "oslokommune"
-	repo := "skjema-iac-terraform"
-	workflowName := "update_container_image_version.yml"
+	if client == nil {
+		return errNoClient
+	}
+
+	settings := config.ForService(clusterName, serviceName).GitHub
+	owner := settings.PackagesOwner
+	repo := settings.WorkflowRepository
+	workflowName := settings.Workflow
 
 	payload := map[string]interface{}{
 		"application":   serviceName,
-		"image_version": fmt.Sprintf("ghcr.io/oslokommune/%s", version),
+		"image_version": fmt.Sprintf("ghcr.io/%s/%s", owner, version),
 		"environment":   clusterName,
 	}
 
 	event := github.CreateWorkflowDispatchEventRequest{
-		Ref:    "master",
+		Ref:    settings.WorkflowRef,
 		Inputs: payload,
 	}
 
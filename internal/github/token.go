@@ -0,0 +1,45 @@
+package github
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FindToken returns the first GitHub token found in GITHUB_TOKEN, GH_TOKEN, the configured token or the gh
+// tool. An empty string is returned if no token is found
+func FindToken(configured string) string {
+	for _, name := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			log.Debug().Msgf("Using github token from %s", name)
+			return token
+		}
+	}
+
+	if configured != "" {
+		log.Debug().Msg("Using github token from the configuration file")
+		return configured
+	}
+
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+
+	// retrieve github token from gh tool
+	builder := new(strings.Builder)
+	c := exec.Command("gh", "auth", "token")
+	c.Stdout = builder
+	if err := c.Run(); err != nil {
+		log.Warn().Err(err).Msg("Failed to load token from gh tool")
+		return ""
+	}
+
+	return strings.TrimSpace(builder.String())
+}
+
+// IsConfigured returns true if a client has been created
+func IsConfigured() bool {
+	return client != nil
+}
@@ -0,0 +1,177 @@
+// Package history builds a timeline of deployments to ECS services and lambda functions: which version was
+// deployed, when and by whom
+package history
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/config"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// Sources of history items
+const (
+	SourceSSM    = "ssm"
+	SourceECS    = "ecs"
+	SourceLambda = "lambda"
+)
+
+// correlationWindow is how far apart a change of the image tag parameter and the task definition revision or the
+// ECS deployment of the image may be to be counted as the same deployment
+const correlationWindow = 10 * time.Minute
+
+// maxHistoryRevisions is the number of task definition revisions the changes of the image tag parameter are matched with
+const maxHistoryRevisions = 10
+
+// ForService returns the deployments of a service, newest first. The history of the image tag parameter tells which
+// image was deployed, when and by whom, and each change is matched with the task definition revision running the
+// image. The deployments ECS keeps for the service are merged into the change they rolled out, or shown on their own
+// if there is none, e.g. when the service was updated without s9k. A missing parameter is not an error, the service
+// may not use one
+func ForService(clusterName string, service types.Service) []aws.LogItem {
+	serviceName := awssdk.ToString(service.ServiceName)
+
+	var changes []aws.LogItem
+	parameter := config.ForService(clusterName, serviceName).ImageTagParameterName()
+	if parameter != "" {
+		var err error
+		changes, err = aws.FetchParameterHistory(parameter)
+		if err != nil {
+			log.Error().Err(err).Msgf("No history for image tag parameter %s", parameter)
+		}
+	}
+
+	return correlate(serviceName, changes, service.Deployments, taskDefinitionRevisions(service))
+}
+
+// ForFunction returns the deployments of a lambda function found in the tags set when a version is deployed. Only
+// the last deployment is known
+func ForFunction(tags map[string]string) []aws.LogItem {
+	version, found := tags[aws.LastDeployedTag]
+	if !found {
+		return []aws.LogItem{}
+	}
+
+	updated, _ := time.Parse(time.RFC3339, tags[aws.LastDeployedAtTag])
+
+	return []aws.LogItem{
+		{
+			Updated: updated,
+			User:    tags[aws.LastDeployedByTag],
+			Version: version,
+			Source:  SourceLambda,
+		},
+	}
+}
+
+// correlate returns a row for each deployment of the service. The changes of the image tag parameter are matched
+// with the newest revision running the image that was registered before the change, and the deployments of ECS with
+// the change of the same revision made at about the same time
+func correlate(serviceName string, changes []aws.LogItem, deployments []types.Deployment, revisions []types.TaskDefinition) []aws.LogItem {
+	items := make([]aws.LogItem, 0, len(changes)+len(deployments))
+
+	for _, change := range changes {
+		change.Source = SourceSSM
+		for _, revision := range revisions {
+			registered := awssdk.ToTime(revision.RegisteredAt)
+			if imageTag(serviceName, revision) == change.Version && !registered.After(change.Updated.Add(correlationWindow)) {
+				change.Revision = aws.ShortenTaskDefArn(revision.TaskDefinitionArn)
+				break
+			}
+		}
+		items = append(items, change)
+	}
+
+	for _, d := range deployments {
+		revision := aws.ShortenTaskDefArn(d.TaskDefinition)
+		created := awssdk.ToTime(d.CreatedAt)
+
+		i := slices.IndexFunc(items, func(item aws.LogItem) bool {
+			return item.Source == SourceSSM && item.Revision == revision && within(item.Updated, created, correlationWindow)
+		})
+		if i >= 0 {
+			items[i].Source = SourceSSM + ", " + SourceECS
+			continue
+		}
+
+		// ECS does not record who started a deployment, so the user is empty
+		item := aws.LogItem{
+			Updated:  created,
+			Version:  revision,
+			Revision: revision,
+			Source:   SourceECS,
+		}
+		if r, found := lo.Find(revisions, func(r types.TaskDefinition) bool {
+			return awssdk.ToString(r.TaskDefinitionArn) == awssdk.ToString(d.TaskDefinition)
+		}); found {
+			item.Version = imageTag(serviceName, r)
+		}
+		items = append(items, item)
+	}
+
+	sortNewestFirst(items)
+
+	return items
+}
+
+// taskDefinitionRevisions returns the latest revisions of the task definition family of the service and the
+// revisions of its deployments, newest first
+func taskDefinitionRevisions(service types.Service) []types.TaskDefinition {
+	name := aws.ShortenTaskDefArn(service.TaskDefinition)
+	family, _, _ := strings.Cut(name, ":")
+	if family == "" {
+		return nil
+	}
+
+	revisions, err := aws.ListTaskDefinitionRevisions(family, maxHistoryRevisions)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list revisions of task definition %s", family)
+	}
+
+	missing := make([]string, 0)
+	for _, d := range service.Deployments {
+		arn := awssdk.ToString(d.TaskDefinition)
+		if !slices.ContainsFunc(revisions, func(r types.TaskDefinition) bool { return awssdk.ToString(r.TaskDefinitionArn) == arn }) {
+			missing = append(missing, arn)
+		}
+	}
+
+	if len(missing) > 0 {
+		deployed, err := aws.GetTaskDefinitions(lo.Uniq(missing))
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to read task definitions of the deployments of %s", awssdk.ToString(service.ServiceName))
+		}
+		revisions = append(revisions, deployed...)
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+
+	return revisions
+}
+
+// imageTag returns the tag of the image of the service container in the task definition
+func imageTag(serviceName string, taskDefinition types.TaskDefinition) string {
+	image := aws.ServiceImage(taskDefinition, serviceName)
+	return utils.RemoveAllBeforeLastChar(":", &image)
+}
+
+func within(a, b time.Time, window time.Duration) bool {
+	return a.Sub(b).Abs() <= window
+}
+
+func sortNewestFirst(items []aws.LogItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Updated.After(items[j].Updated)
+	})
+}
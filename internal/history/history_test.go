@@ -0,0 +1,147 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/aws/fake"
+	"github.com/bsek/s9k/internal/config"
+)
+
+func TestForService(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	previous := config.Get()
+	config.Set(config.Default())
+	t.Cleanup(func() { config.Set(previous) })
+
+	services, err := aws.DescribeClusterServices("dev")
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+	var items []aws.LogItem
+	for _, service := range services {
+		if awssdk.ToString(service.ServiceName) == "api" {
+			items = ForService("dev", service)
+		}
+	}
+
+	changes := 0
+	for i, item := range items {
+		if i > 0 && item.Updated.After(items[i-1].Updated) {
+			t.Errorf("item %d is newer than item %d", i, i-1)
+		}
+		if !strings.HasPrefix(item.Source, SourceSSM) {
+			continue
+		}
+		changes++
+		if item.Revision == "" || item.User == "" {
+			t.Errorf("got parameter change %+v, want the revision and the user", item)
+		}
+		if item.Version == "sha-4f1c2ab" && item.Revision != "dev-api:3" {
+			t.Errorf("got revision %s for the current image, want dev-api:3", item.Revision)
+		}
+	}
+	if changes != 3 {
+		t.Errorf("got %d parameter changes in %+v, want 3", changes, items)
+	}
+}
+
+func TestForFunction(t *testing.T) {
+	tags := map[string]string{
+		aws.LastDeployedTag:   "v1.2.0.zip",
+		aws.LastDeployedAtTag: "2024-05-17T14:30:00Z",
+		aws.LastDeployedByTag: "arn:aws:iam::123456789012:user/alice",
+	}
+
+	items := ForFunction(tags)
+
+	want := aws.LogItem{
+		Updated: time.Date(2024, 5, 17, 14, 30, 0, 0, time.UTC),
+		User:    "arn:aws:iam::123456789012:user/alice",
+		Version: "v1.2.0.zip",
+		Source:  SourceLambda,
+	}
+	if len(items) != 1 || items[0] != want {
+		t.Errorf("got %+v, want %+v", items, want)
+	}
+
+	if items := ForFunction(map[string]string{}); len(items) != 0 {
+		t.Errorf("got %+v for a function that was never deployed, want no items", items)
+	}
+}
+
+var now = time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+
+func revision(number int32, tag string, registered time.Time) types.TaskDefinition {
+	return types.TaskDefinition{
+		TaskDefinitionArn: awssdk.String(fmt.Sprintf("arn:aws:ecs:eu-north-1:123456789012:task-definition/dev-api:%d", number)),
+		Revision:          number,
+		RegisteredAt:      &registered,
+		ContainerDefinitions: []types.ContainerDefinition{
+			{Image: awssdk.String("123456789012.dkr.ecr.eu-north-1.amazonaws.com/dev-api:" + tag)},
+		},
+	}
+}
+
+func deployment(number int32, created time.Time) types.Deployment {
+	return types.Deployment{
+		TaskDefinition: awssdk.String(fmt.Sprintf("arn:aws:ecs:eu-north-1:123456789012:task-definition/dev-api:%d", number)),
+		CreatedAt:      &created,
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	revisions := []types.TaskDefinition{
+		revision(3, "sha-c", now.Add(-time.Hour)),
+		revision(2, "sha-b", now.Add(-48*time.Hour)),
+		revision(1, "sha-a", now.Add(-72*time.Hour)),
+	}
+
+	// sha-a is deployed, then sha-b, rolled back to sha-a, and sha-c deployed without changing the parameter
+	changes := []aws.LogItem{
+		{Updated: now.Add(-24*time.Hour + 20*time.Second), Version: "sha-a", User: "bob"},
+		{Updated: now.Add(-48*time.Hour + 20*time.Second), Version: "sha-b", User: "alice"},
+		{Updated: now.Add(-72*time.Hour + 20*time.Second), Version: "sha-a", User: "alice"},
+	}
+	deployments := []types.Deployment{
+		deployment(3, now.Add(-time.Hour)),
+		deployment(1, now.Add(-24*time.Hour)),
+	}
+
+	items := correlate("api", changes, deployments, revisions)
+
+	want := []aws.LogItem{
+		{Updated: now.Add(-time.Hour), Version: "sha-c", Revision: "dev-api:3", Source: SourceECS},
+		{Updated: now.Add(-24*time.Hour + 20*time.Second), Version: "sha-a", Revision: "dev-api:1", User: "bob", Source: "ssm, ecs"},
+		{Updated: now.Add(-48*time.Hour + 20*time.Second), Version: "sha-b", Revision: "dev-api:2", User: "alice", Source: SourceSSM},
+		{Updated: now.Add(-72*time.Hour + 20*time.Second), Version: "sha-a", Revision: "dev-api:1", User: "alice", Source: SourceSSM},
+	}
+
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d: %v", len(items), len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("item %d: got %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestCorrelateChangeBeforeRevision(t *testing.T) {
+	// a revision registered long after the change did not roll it out
+	revisions := []types.TaskDefinition{revision(2, "sha-a", now)}
+	changes := []aws.LogItem{{Updated: now.Add(-24 * time.Hour), Version: "sha-a"}}
+
+	items := correlate("api", changes, nil, revisions)
+
+	if len(items) != 1 || items[0].Revision != "" {
+		t.Errorf("got %+v, want the change without revision", items)
+	}
+}
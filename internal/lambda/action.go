@@ -1,32 +1,49 @@
 package lambda
 
 import (
-	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"fmt"
+
 	"github.com/rivo/tview"
 
+	"github.com/bsek/s9k/internal/data"
 	"github.com/bsek/s9k/internal/ui"
 )
 
-func createActionForm(functionName, logGroupName string, arch lambdatypes.Architecture) {
+// createActionForm shows the actions of a function as a vertical menu, reload reads the functions again after a
+// change. An action is picked with Enter or its shortcut, Esc closes the menu
+func createActionForm(function data.Function, reload func()) {
 	const ACTION_FORM = "action_form"
 
-	modal := tview.NewModal().
-		SetText("What do you want to do?").
-		AddButtons([]string{"Show logs", "Restart service", "Deploy version", "Close"}).
-		SetDoneFunc(func(_ int, buttonLabel string) {
-			if buttonLabel == "Show logs" {
-				showLogs(logGroupName)
-			}
-			if buttonLabel == "Restart service" {
-				restart(functionName)
-			}
-			if buttonLabel == "Deploy version" {
-				deploy(functionName, arch)
-			}
-			if buttonLabel == "Close" {
-				ui.App.Content.RemovePage(ACTION_FORM)
-			}
-		})
-
-	ui.App.Content.AddAndSwitchToPage(ACTION_FORM, modal, true)
+	functionName := *function.FunctionName
+
+	actions := []struct {
+		label    string
+		shortcut rune
+		selected func()
+	}{
+		{"Show logs", 'l', func() { showLogs(*function.LoggingConfig.LogGroup) }},
+		{"Metrics", 'm', func() { showInvocations(functionName) }},
+		{"Invoke", 'i', func() { invoke(functionName) }},
+		{"Restart service", 'r', func() { restart(functionName) }},
+		{"Deploy version", 'd', func() { deploy(functionName, function.Architectures[0]) }},
+		{"Versions", 'v', func() { showVersions(functionName) }},
+		{"Configure", 'c', func() { configure(function, reload) }},
+		{"Show history", 'h', func() { showHistory(functionName) }},
+		{"Close", 'q', func() { ui.App.Content.RemovePage(ACTION_FORM) }},
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", functionName))
+
+	for _, action := range actions {
+		list.AddItem(action.label, "", action.shortcut, action.selected)
+	}
+
+	list.SetDoneFunc(func() {
+		ui.App.Content.RemovePage(ACTION_FORM)
+	})
+
+	modalPage := ui.CreateModalPage(list, nil, max(len(functionName)+6, 30), len(actions)+2, ACTION_FORM)
+
+	ui.App.Content.AddAndSwitchToPage(ACTION_FORM, modalPage, true)
 }
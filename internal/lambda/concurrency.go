@@ -0,0 +1,280 @@
+package lambda
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// ThrottledConcurrencyTag is the function tag holding the reserved concurrency of a throttled function, so it can
+// be restored. The value is none when the function had no reserved concurrency
+const ThrottledConcurrencyTag = "s9k:throttled-reserved-concurrency"
+
+const noReservedConcurrency = "none"
+
+// editConcurrency opens a dialog to change the reserved concurrency of the function and the provisioned concurrency
+// of one of its aliases. The changes are confirmed before they are applied. Changing the reserved concurrency of a
+// throttled function ends the throttling, the saved reserved concurrency is removed
+func editConcurrency(function data.Function, reload func()) {
+	const CONCURRENCY_DIALOG = "concurrency_dialog"
+	pages := ui.App.Content
+	functionName := awssdk.ToString(function.FunctionName)
+
+	aliases, err := aws.ListLambdaAliases(functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list aliases of %s", functionName)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to list aliases of %s, check log file", functionName))
+		return
+	}
+
+	configs, err := aws.ListProvisionedConcurrency(functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read provisioned concurrency of %s", functionName)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to read provisioned concurrency of %s, check log file", functionName))
+		return
+	}
+
+	// provisioned concurrency by alias name
+	provisioned := map[string]int32{}
+	for _, config := range configs {
+		provisioned[utils.RemoveAllBeforeLastChar(":", config.FunctionArn)] = awssdk.ToInt32(config.RequestedProvisionedConcurrentExecutions)
+	}
+
+	aliasNames := lo.Map(aliases, func(alias lambdatypes.AliasConfiguration, _ int) string {
+		return awssdk.ToString(alias.Name)
+	})
+
+	_, throttled := function.Tags[ThrottledConcurrencyTag]
+
+	reserved := ""
+	if function.ReservedConcurrency != nil {
+		reserved = utils.I32ToString(*function.ReservedConcurrency)
+	}
+
+	form := tview.NewForm().
+		AddInputField("Reserved concurrency", reserved, 8, tview.InputFieldInteger, nil)
+
+	reservedInput := form.GetFormItemByLabel("Reserved concurrency").(*tview.InputField)
+
+	var aliasInput *tview.DropDown
+	var provisionedInput *tview.InputField
+	if len(aliasNames) > 0 {
+		form.
+			AddDropDown("Alias", aliasNames, 0, nil).
+			AddInputField("Provisioned concurrency", utils.I32ToString(provisioned[aliasNames[0]]), 8, tview.InputFieldInteger, nil)
+
+		aliasInput = form.GetFormItemByLabel("Alias").(*tview.DropDown)
+		provisionedInput = form.GetFormItemByLabel("Provisioned concurrency").(*tview.InputField)
+
+		aliasInput.SetSelectedFunc(func(alias string, _ int) {
+			provisionedInput.SetText(utils.I32ToString(provisioned[alias]))
+		})
+	}
+
+	form.
+		AddButton("Save", func() {
+			changes := []string{}
+			apply := []func() error{}
+
+			if text := strings.TrimSpace(reservedInput.GetText()); text != reserved {
+				if text == "" {
+					changes = append(changes, "remove the reserved concurrency")
+					apply = append(apply, func() error { return aws.RemoveReservedConcurrency(functionName) })
+				} else {
+					concurrency, err := strconv.Atoi(text)
+					if err != nil || concurrency < 0 {
+						ui.CreateMessageBox(fmt.Sprintf("%q is not a valid reserved concurrency", text))
+						return
+					}
+					changes = append(changes, fmt.Sprintf("reserve %d concurrent executions", concurrency))
+					apply = append(apply, func() error { return aws.SetReservedConcurrency(functionName, int32(concurrency)) })
+				}
+
+				// the new reserved concurrency replaces the one saved when the function was throttled
+				if throttled {
+					changes = append(changes, "end the throttling")
+					apply = append(apply, func() error {
+						return aws.UntagLambdaFunction(awssdk.ToString(function.FunctionArn), ThrottledConcurrencyTag)
+					})
+				}
+			}
+
+			if aliasInput != nil {
+				_, alias := aliasInput.GetCurrentOption()
+				concurrency, err := strconv.Atoi(provisionedInput.GetText())
+				if err != nil || concurrency < 0 {
+					ui.CreateMessageBox(fmt.Sprintf("%q is not a valid provisioned concurrency", provisionedInput.GetText()))
+					return
+				}
+				if int32(concurrency) != provisioned[alias] {
+					changes = append(changes, fmt.Sprintf("provision %d concurrent executions on alias %s", concurrency, alias))
+					apply = append(apply, func() error { return aws.SetProvisionedConcurrency(functionName, alias, int32(concurrency)) })
+				}
+			}
+
+			if len(changes) == 0 {
+				ui.CreateMessageBox(fmt.Sprintf("The concurrency of %s has not been changed", functionName))
+				return
+			}
+
+			pages.RemovePage(CONCURRENCY_DIALOG)
+			ui.CreateConfirmBox(fmt.Sprintf("Do you want to %s for %s?", strings.Join(changes, " and "), functionName), func() {
+				for _, f := range apply {
+					if err := f(); err != nil {
+						log.Error().Err(err).Msgf("Failed to change concurrency of %s", functionName)
+						ui.CreateMessageBox(fmt.Sprintf("Failed to change the concurrency of %s: %v", functionName, err))
+						reload()
+						return
+					}
+				}
+				reload()
+				ui.CreateMessageBox(fmt.Sprintf("The concurrency of %s has been changed", functionName))
+			}, func() {})
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(CONCURRENCY_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(CONCURRENCY_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" Concurrency of %s ", functionName)).SetTitleAlign(tview.AlignLeft)
+
+	height := 7
+	if aliasInput != nil {
+		height = 11
+	}
+	modalPage := ui.CreateModalPage(form, nil, 60, height, CONCURRENCY_DIALOG)
+
+	pages.AddPage(CONCURRENCY_DIALOG, modalPage, true, true)
+}
+
+// confirmThrottle asks for confirmation and stops all invocations of the function by setting its reserved
+// concurrency to 0. The previous reserved concurrency is saved in a tag on the function
+func confirmThrottle(function data.Function, reload func()) {
+	functionName := awssdk.ToString(function.FunctionName)
+
+	ui.CreateConfirmBox(fmt.Sprintf("Do you want to throttle %s to zero? Every invocation of the function will be rejected until it is restored.", functionName), func() {
+		if err := throttle(function); err != nil {
+			log.Error().Err(err).Msgf("Failed to throttle %s", functionName)
+			ui.CreateMessageBox(fmt.Sprintf("Failed to throttle %s: %v", functionName, err))
+			return
+		}
+		reload()
+		ui.CreateMessageBox(fmt.Sprintf("%s is throttled to zero", functionName))
+	}, func() {})
+}
+
+// confirmRestore asks for confirmation and restores the reserved concurrency a throttled function had before
+func confirmRestore(function data.Function, reload func()) {
+	functionName := awssdk.ToString(function.FunctionName)
+
+	ui.CreateConfirmBox(fmt.Sprintf("Do you want to restore the concurrency of %s?", functionName), func() {
+		if err := restore(function); err != nil {
+			log.Error().Err(err).Msgf("Failed to restore %s", functionName)
+			ui.CreateMessageBox(fmt.Sprintf("Failed to restore %s: %v", functionName, err))
+			return
+		}
+		reload()
+		ui.CreateMessageBox(fmt.Sprintf("The concurrency of %s is restored", functionName))
+	}, func() {})
+}
+
+func throttle(function data.Function) error {
+	functionName := awssdk.ToString(function.FunctionName)
+	arn := awssdk.ToString(function.FunctionArn)
+
+	// the function is read again, it may have been throttled since the account data was refreshed
+	output, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		return err
+	}
+	if _, found := output.Tags[ThrottledConcurrencyTag]; found {
+		return errors.New("the function is already throttled")
+	}
+
+	previous := noReservedConcurrency
+	if output.Concurrency != nil && output.Concurrency.ReservedConcurrentExecutions != nil {
+		previous = utils.I32ToString(*output.Concurrency.ReservedConcurrentExecutions)
+	}
+
+	// the previous value is saved first, a function is never throttled without a way back
+	if err := aws.TagLambdaFunction(arn, map[string]string{ThrottledConcurrencyTag: previous}); err != nil {
+		return fmt.Errorf("failed to save the reserved concurrency: %w", err)
+	}
+
+	if err := aws.SetReservedConcurrency(functionName, 0); err != nil {
+		// the tag is removed so the function is not seen as throttled
+		if untagErr := aws.UntagLambdaFunction(arn, ThrottledConcurrencyTag); untagErr != nil {
+			log.Error().Err(untagErr).Msgf("Failed to remove tag %s from %s", ThrottledConcurrencyTag, functionName)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func restore(function data.Function) error {
+	functionName := awssdk.ToString(function.FunctionName)
+	arn := awssdk.ToString(function.FunctionArn)
+
+	output, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		return err
+	}
+
+	previous, found := output.Tags[ThrottledConcurrencyTag]
+	if !found {
+		return errors.New("the function is not throttled")
+	}
+
+	if previous == noReservedConcurrency {
+		err = aws.RemoveReservedConcurrency(functionName)
+	} else {
+		concurrency, parseErr := strconv.ParseInt(previous, 10, 32)
+		if parseErr != nil {
+			return fmt.Errorf("invalid saved reserved concurrency %q", previous)
+		}
+		err = aws.SetReservedConcurrency(functionName, int32(concurrency))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := aws.UntagLambdaFunction(arn, ThrottledConcurrencyTag); err != nil {
+		return fmt.Errorf("restored, but failed to remove the saved reserved concurrency: %w", err)
+	}
+
+	return nil
+}
+
+// formatReservedConcurrency returns the reserved concurrency of the function, empty when it has none
+func formatReservedConcurrency(function data.Function) string {
+	if function.ReservedConcurrency == nil {
+		return ""
+	}
+	if _, found := function.Tags[ThrottledConcurrencyTag]; found {
+		return fmt.Sprintf("%d (throttled)", *function.ReservedConcurrency)
+	}
+	return utils.I32ToString(*function.ReservedConcurrency)
+}
+
+// formatProvisionedConcurrency returns the provisioned concurrency of the function, empty when it has none
+func formatProvisionedConcurrency(function data.Function) string {
+	if function.ProvisionedConcurrency == 0 {
+		return ""
+	}
+	return utils.I32ToString(function.ProvisionedConcurrency)
+}
@@ -0,0 +1,101 @@
+package lambda
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambdaTypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/aws/fake"
+	"github.com/bsek/s9k/internal/data"
+)
+
+// demoFunction returns the function as it is read when the account data is refreshed
+func demoFunction(t *testing.T, functionName string) data.Function {
+	t.Helper()
+
+	output, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		t.Fatalf("failed to read function %s: %v", functionName, err)
+	}
+
+	return data.Function{FunctionConfiguration: lambdaTypes.FunctionConfiguration{
+		FunctionName: output.Configuration.FunctionName,
+		FunctionArn:  output.Configuration.FunctionArn,
+	}}
+}
+
+// reservedConcurrency returns the reserved concurrency of the function, nil when it has none
+func reservedConcurrency(t *testing.T, functionName string) *int32 {
+	t.Helper()
+
+	output, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		t.Fatalf("failed to read function %s: %v", functionName, err)
+	}
+	if output.Concurrency == nil {
+		return nil
+	}
+	return output.Concurrency.ReservedConcurrentExecutions
+}
+
+func TestThrottleAndRestore(t *testing.T) {
+	tests := []struct {
+		functionName string
+		want         *int32
+	}{
+		{functionName: "orders-handler", want: awssdk.Int32(100)},
+		{functionName: "email-sender", want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.functionName, func(t *testing.T) {
+			fake.NewBackend(fake.DemoFixtures()).Install()
+			function := demoFunction(t, test.functionName)
+
+			if err := throttle(function); err != nil {
+				t.Fatalf("throttle() failed: %v", err)
+			}
+			if got := reservedConcurrency(t, test.functionName); got == nil || *got != 0 {
+				t.Errorf("got reserved concurrency %v after throttling, want 0", got)
+			}
+			if err := throttle(function); err == nil {
+				t.Error("throttling a throttled function succeeded")
+			}
+
+			if err := restore(function); err != nil {
+				t.Fatalf("restore() failed: %v", err)
+			}
+			if got := reservedConcurrency(t, test.functionName); awssdk.ToInt32(got) != awssdk.ToInt32(test.want) || (got == nil) != (test.want == nil) {
+				t.Errorf("got reserved concurrency %v after restoring, want %v", got, test.want)
+			}
+			if err := restore(function); err == nil {
+				t.Error("restoring a function that is not throttled succeeded")
+			}
+		})
+	}
+}
+
+func TestFormatReservedConcurrency(t *testing.T) {
+	tests := []struct {
+		function data.Function
+		want     string
+	}{
+		{function: data.Function{}, want: ""},
+		{function: data.Function{ReservedConcurrency: awssdk.Int32(100)}, want: "100"},
+		{
+			function: data.Function{
+				ReservedConcurrency: awssdk.Int32(0),
+				Tags:                map[string]string{ThrottledConcurrencyTag: "100"},
+			},
+			want: "0 (throttled)",
+		},
+	}
+
+	for _, test := range tests {
+		if got := formatReservedConcurrency(test.function); got != test.want {
+			t.Errorf("formatReservedConcurrency() = %q, want %q", got, test.want)
+		}
+	}
+}
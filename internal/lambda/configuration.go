@@ -0,0 +1,263 @@
+package lambda
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+const (
+	CONFIGURATION_DIALOG = "configuration_dialog"
+	DIFF_DIALOG          = "configuration_diff_dialog"
+)
+
+// environmentKey is the format lambda accepts for the names of environment variables
+var environmentKey = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// configure opens an editor for the configuration of the function. The configuration is read again, it may have
+// changed since the account data was refreshed. The changes are shown for review before the function is updated
+func configure(function data.Function, reload func()) {
+	pages := ui.App.Content
+	functionName := awssdk.ToString(function.FunctionName)
+
+	current, err := aws.GetLambdaConfiguration(functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read configuration of %s", functionName)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to read configuration of %s, check log file", functionName))
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("Description", current.Description, 60, nil, nil).
+		AddInputField("Memory size (MB)", strconv.Itoa(int(current.MemorySize)), 8, tview.InputFieldInteger, nil).
+		AddInputField("Timeout (s)", strconv.Itoa(int(current.Timeout)), 8, tview.InputFieldInteger, nil).
+		AddInputField("Ephemeral storage (MB)", strconv.Itoa(int(current.EphemeralStorage)), 8, tview.InputFieldInteger, nil).
+		AddTextArea("Environment", formatEnvironment(current.Environment), 0, 10, 0, nil)
+
+	descriptionInput := form.GetFormItemByLabel("Description").(*tview.InputField)
+	memoryInput := form.GetFormItemByLabel("Memory size (MB)").(*tview.InputField)
+	timeoutInput := form.GetFormItemByLabel("Timeout (s)").(*tview.InputField)
+	storageInput := form.GetFormItemByLabel("Ephemeral storage (MB)").(*tview.InputField)
+	environmentInput := form.GetFormItemByLabel("Environment").(*tview.TextArea)
+
+	form.
+		AddButton("Review", func() {
+			updated, err := parseConfiguration(descriptionInput.GetText(), memoryInput.GetText(), timeoutInput.GetText(),
+				storageInput.GetText(), environmentInput.GetText())
+			if err != nil {
+				ui.CreateMessageBox(err.Error())
+				return
+			}
+
+			changes := diffConfiguration(*current, updated)
+			if len(changes) == 0 {
+				ui.CreateMessageBox(fmt.Sprintf("The configuration of %s has not been changed", functionName))
+				return
+			}
+
+			// the variables are only sent when they are changed, so an update of the other settings never
+			// touches them
+			if len(diffEnvironment(current.Environment, updated.Environment)) == 0 {
+				updated.Environment = nil
+			}
+
+			reviewConfiguration(functionName, changes, updated, reload)
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(CONFIGURATION_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(CONFIGURATION_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" ⚙ Configure %s ", functionName)).SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 100, 25, CONFIGURATION_DIALOG)
+
+	pages.AddPage(CONFIGURATION_DIALOG, modalPage, true, true)
+}
+
+// reviewConfiguration shows the changes on top of the editor. Going back keeps the edited values
+func reviewConfiguration(functionName string, changes []string, updated aws.LambdaConfiguration, reload func()) {
+	pages := ui.App.Content
+
+	colored := make([]string, 0, len(changes))
+	for _, change := range changes {
+		color := "yellow"
+		switch change[0] {
+		case '+':
+			color = "green"
+		case '-':
+			color = "red"
+		}
+		colored = append(colored, fmt.Sprintf("[%s]%s[-]", color, tview.Escape(change)))
+	}
+
+	form := tview.NewForm().
+		AddTextView("", strings.Join(colored, "\n"), 0, min(len(changes), 15), true, true)
+
+	form.
+		AddButton("Apply", func() {
+			pages.RemovePage(DIFF_DIALOG)
+			pages.RemovePage(CONFIGURATION_DIALOG)
+			applyConfiguration(functionName, updated, reload)
+		}).
+		AddButton("Back", func() {
+			pages.RemovePage(DIFF_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(DIFF_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" ⚙ Changes to %s ", functionName)).SetTitleAlign(tview.AlignLeft)
+
+	// focus the apply button, the changes only need scrolling when there are many
+	form.SetFocus(form.GetFormItemCount())
+
+	modalPage := ui.CreateModalPage(form, nil, 100, min(len(changes), 15)+7, DIFF_DIALOG)
+
+	pages.AddPage(DIFF_DIALOG, modalPage, true, true)
+}
+
+// applyConfiguration updates the function in the background, the update is done when lambda reports it as
+// successful. The functions are reloaded to show the new configuration
+func applyConfiguration(functionName string, updated aws.LambdaConfiguration, reload func()) {
+	ui.CreateMessageBox(fmt.Sprintf("Updating the configuration of %s...", functionName))
+
+	go func() {
+		err := aws.UpdateLambdaConfiguration(functionName, updated)
+
+		ui.App.TviewApp.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to update configuration of %s", functionName)
+				ui.CreateMessageBox(fmt.Sprintf("Failed to update the configuration of %s: %v", functionName, err))
+				return
+			}
+
+			reload()
+			ui.CreateMessageBox(fmt.Sprintf("The configuration of %s has been updated", functionName))
+		})
+	}()
+}
+
+// parseConfiguration validates the values of the editor against the limits of lambda
+func parseConfiguration(description, memory, timeout, storage, environment string) (aws.LambdaConfiguration, error) {
+	configuration := aws.LambdaConfiguration{Description: description}
+
+	var err error
+	if configuration.MemorySize, err = parseLimited("memory size", memory, 128, 10240); err != nil {
+		return configuration, err
+	}
+	if configuration.Timeout, err = parseLimited("timeout", timeout, 1, 900); err != nil {
+		return configuration, err
+	}
+	if configuration.EphemeralStorage, err = parseLimited("ephemeral storage", storage, 512, 10240); err != nil {
+		return configuration, err
+	}
+	if configuration.Environment, err = parseEnvironment(environment); err != nil {
+		return configuration, err
+	}
+
+	return configuration, nil
+}
+
+func parseLimited(name, text string, min, max int) (int32, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("the %s must be between %d and %d", name, min, max)
+	}
+	return int32(value), nil
+}
+
+// parseEnvironment reads environment variables written as KEY=value, one per line. Empty lines and lines starting
+// with # are skipped
+func parseEnvironment(text string) (map[string]string, error) {
+	variables := map[string]string{}
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || !environmentKey.MatchString(key) {
+			return nil, fmt.Errorf("line %d of the environment is not a valid KEY=value", i+1)
+		}
+		if _, exists := variables[key]; exists {
+			return nil, fmt.Errorf("environment variable %s is set more than once", key)
+		}
+
+		variables[key] = value
+	}
+
+	return variables, nil
+}
+
+func formatEnvironment(variables map[string]string) string {
+	lines := []string{}
+	for _, key := range slices.Sorted(maps.Keys(variables)) {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, variables[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffConfiguration lists the changes from the current to the updated configuration, one per line. Lines start
+// with + for added, - for removed and ~ for changed values
+func diffConfiguration(current, updated aws.LambdaConfiguration) []string {
+	changes := []string{}
+
+	changed := func(name, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("~ %s: %s → %s", name, from, to))
+		}
+	}
+
+	changed("Description", strconv.Quote(current.Description), strconv.Quote(updated.Description))
+	changed("Memory size", fmt.Sprintf("%d MB", current.MemorySize), fmt.Sprintf("%d MB", updated.MemorySize))
+	changed("Timeout", fmt.Sprintf("%d s", current.Timeout), fmt.Sprintf("%d s", updated.Timeout))
+	changed("Ephemeral storage", fmt.Sprintf("%d MB", current.EphemeralStorage), fmt.Sprintf("%d MB", updated.EphemeralStorage))
+
+	return append(changes, diffEnvironment(current.Environment, updated.Environment)...)
+}
+
+// diffEnvironment lists the added, removed and changed environment variables, sorted by name
+func diffEnvironment(current, updated map[string]string) []string {
+	changes := []string{}
+
+	keys := slices.Sorted(maps.Keys(current))
+	for _, key := range slices.Sorted(maps.Keys(updated)) {
+		if _, found := current[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		from, inCurrent := current[key]
+		to, inUpdated := updated[key]
+
+		switch {
+		case !inCurrent:
+			changes = append(changes, fmt.Sprintf("+ %s=%s", key, to))
+		case !inUpdated:
+			changes = append(changes, fmt.Sprintf("- %s=%s", key, from))
+		case from != to:
+			changes = append(changes, fmt.Sprintf("~ %s: %s → %s", key, from, to))
+		}
+	}
+
+	return changes
+}
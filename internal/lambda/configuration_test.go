@@ -0,0 +1,109 @@
+package lambda
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func TestParseEnvironment(t *testing.T) {
+	text := `
+# mail settings
+SMTP_HOST = smtp.example.com
+FROM=no-reply@example.com
+QUERY=a=b&c=d
+EMPTY=
+`
+	got, err := parseEnvironment(text)
+	if err != nil {
+		t.Fatalf("parseEnvironment failed: %v", err)
+	}
+
+	want := map[string]string{
+		"SMTP_HOST": " smtp.example.com",
+		"FROM":      "no-reply@example.com",
+		"QUERY":     "a=b&c=d",
+		"EMPTY":     "",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseEnvironmentInvalid(t *testing.T) {
+	tests := []string{
+		"SMTP_HOST",
+		"1HOST=a",
+		"SMTP-HOST=a",
+		"=a",
+		"HOST=a\nHOST=b",
+	}
+
+	for _, text := range tests {
+		if got, err := parseEnvironment(text); err == nil {
+			t.Errorf("parseEnvironment(%q) = %v, want an error", text, got)
+		}
+	}
+}
+
+func TestFormatEnvironmentRoundTrip(t *testing.T) {
+	variables := map[string]string{"B": "2", "A": "x=1"}
+
+	text := formatEnvironment(variables)
+	if text != "A=x=1\nB=2" {
+		t.Errorf("got %q, want the variables sorted by key", text)
+	}
+
+	parsed, err := parseEnvironment(text)
+	if err != nil || !maps.Equal(parsed, variables) {
+		t.Errorf("got %v, %v, want %v", parsed, err, variables)
+	}
+}
+
+func TestDiffConfiguration(t *testing.T) {
+	current := aws.LambdaConfiguration{
+		Description:      "Sends emails",
+		MemorySize:       128,
+		Timeout:          3,
+		EphemeralStorage: 512,
+		Environment:      map[string]string{"FROM": "a@example.com", "LEVEL": "info", "OLD": "1"},
+	}
+	updated := current
+	updated.MemorySize = 256
+	updated.Environment = map[string]string{"FROM": "a@example.com", "LEVEL": "debug", "NEW": "2"}
+
+	got := diffConfiguration(current, updated)
+
+	want := []string{
+		"~ Memory size: 128 MB → 256 MB",
+		"~ LEVEL: info → debug",
+		"+ NEW=2",
+		"- OLD=1",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDiffConfigurationUnchanged(t *testing.T) {
+	configuration := aws.LambdaConfiguration{MemorySize: 128, Environment: map[string]string{"A": "1"}}
+
+	if got := diffConfiguration(configuration, configuration); len(got) != 0 {
+		t.Errorf("got %q, want no changes", got)
+	}
+}
+
+func TestDiffEnvironment(t *testing.T) {
+	current := map[string]string{"FROM": "a@example.com", "LEVEL": "info"}
+
+	if got := diffEnvironment(current, map[string]string{"LEVEL": "info", "FROM": "a@example.com"}); len(got) != 0 {
+		t.Errorf("got %q for the same variables, want no changes", got)
+	}
+
+	want := []string{"- FROM=a@example.com", "~ LEVEL: info → debug"}
+	if got := diffEnvironment(current, map[string]string{"LEVEL": "debug"}); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
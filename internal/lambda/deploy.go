@@ -2,27 +2,28 @@ package lambda
 
 import (
 	"fmt"
-	"strings"
 
 	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 
+	"github.com/bsek/s9k/internal/artifacts"
 	"github.com/bsek/s9k/internal/aws"
 	"github.com/bsek/s9k/internal/ui"
 )
 
-func retrieveListOfDeployables(functionName string) []string {
-	versions, err := aws.FetchAvailableVersions(functionName)
+func retrieveListOfDeployables(functionName string) []artifacts.Artifact {
+	source, err := artifacts.ForFunction(functionName)
 	if err != nil {
-		log.Error().Err(err).Msgf("Failed to read versions from s3")
-		return []string{}
+		log.Error().Err(err).Msgf("No artifact source for %s", functionName)
+		return []artifacts.Artifact{}
 	}
 
-	list := make([]string, 0, len(versions))
-	for _, v := range versions {
-		pos := strings.Index(v.Name, "/")
-		list = append(list, v.Name[pos+1:])
+	list, err := source.List()
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read versions from %s", source.Name())
+		return []artifacts.Artifact{}
 	}
 
 	return list
@@ -40,28 +41,50 @@ func createInstallForm(_ string, deployables []string, selected func(string, int
 func deploy(functionName string, arch lambdatypes.Architecture) {
 	const DEPLOY_DIALOG = "deploy_dialog"
 	pages := ui.App.Content
-	var selectedOption string
+	var selected *artifacts.Artifact
+
+	deployables := retrieveListOfDeployables(functionName)
+	versions := lo.Map(deployables, func(a artifacts.Artifact, _ int) string {
+		return a.Version
+	})
 
-	form := createInstallForm(functionName, retrieveListOfDeployables(functionName),
-		func(option string, _ int) {
-			selectedOption = option
+	form := createInstallForm(functionName, versions,
+		func(_ string, index int) {
+			if index >= 0 && index < len(deployables) {
+				selected = &deployables[index]
+			}
 		},
 		func() {
 			pages.RemovePage(DEPLOY_DIALOG)
 		},
 		func() {
-			log.Info().Msgf("Deployed version %s", selectedOption)
+			if selected == nil {
+				pages.RemovePage(DEPLOY_DIALOG)
+				return
+			}
+
+			version := selected.Version
+			log.Info().Msgf("Deployed version %s", version)
+
+			var arn *string
+			var err error
+			if selected.Image != "" {
+				arn, err = aws.DeployLambdaImage(functionName, selected.Image)
+			} else {
+				arn, err = aws.DeployLambdaFunction(functionName, selected.Bucket, selected.Key, arch)
+			}
 
-			arn, err := aws.DeployLambdaFunction(functionName, selectedOption, arch)
 			if err != nil {
-				log.Error().Err(err).Msgf("Failed to update %s to version %s", functionName, selectedOption)
-				ui.CreateMessageBox(fmt.Sprintf("Failed to update %s to version %s", functionName, selectedOption))
+				log.Error().Err(err).Msgf("Failed to update %s to version %s", functionName, version)
+				ui.CreateMessageBox(fmt.Sprintf("Failed to update %s to version %s", functionName, version))
 			} else {
-				err := aws.TagLambdaFunctionWithVersion(*arn, selectedOption)
+				err := aws.TagLambdaFunctionWithVersion(*arn, version)
 				if err != nil {
-					log.Error().Err(err).Msgf("Failed to tag %s with version %s", functionName, selectedOption)
+					log.Error().Err(err).Msgf("Failed to tag %s with version %s", functionName, version)
 				}
-				ui.CreateMessageBox(fmt.Sprintf("%s successfully updated to version %s", functionName, selectedOption))
+				ui.CreateConfirmBox(fmt.Sprintf("%s successfully updated to version %s. Do you want to publish it as a new version of the function?", functionName, version), func() {
+					showVersions(functionName).publish(version)
+				}, func() {})
 			}
 
 			pages.RemovePage(DEPLOY_DIALOG)
@@ -0,0 +1,59 @@
+package lambda
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/history"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+// showHistory shows the deployments of the function recorded in its tags. The function is read again, the tags
+// in the account data are not updated after a deploy
+func showHistory(functionName string) {
+	const HISTORY_DIALOG = "history_dialog"
+	pages := ui.App.Content
+
+	function, err := aws.GetLambdaFunction(functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read function %s", functionName)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to read %s, check log file", functionName))
+		return
+	}
+
+	data := [][]string{}
+	for _, item := range history.ForFunction(function.Tags) {
+		data = append(data, []string{
+			utils.FormatLocalDateTime(item.Updated),
+			item.Version,
+			item.User,
+			item.Source,
+		})
+	}
+
+	historyTable := tview.NewTable().SetSelectable(true, false)
+	historyTable.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🕘 Deployment history of %s ", functionName))
+
+	headers := []string{"Deployed", "Version", "User", "Source"}
+	expansions := []int{1, 2, 2, 1}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft}
+
+	ui.AddTableData(historyTable, headers, data, alignment, expansions, tcell.ColorLightGray, true)
+
+	historyTable.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			pages.RemovePage(HISTORY_DIALOG)
+		}
+	})
+
+	modalPage := ui.CreateModalPage(historyTable, nil, 100, 6, HISTORY_DIALOG)
+
+	pages.AddPage(HISTORY_DIALOG, modalPage, true, true)
+}
@@ -0,0 +1,150 @@
+package lambda
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+var _ ui.ContentPage = (*InvocationsPage)(nil)
+
+// InvocationsPage shows the invocation metrics of a lambda function from CloudWatch as sparklines
+type InvocationsPage struct {
+	Flex         *tview.Flex
+	metrics      *ui.MetricsView
+	functionName string
+	window       ui.MetricWindow
+}
+
+// showInvocations opens the invocations page of a function
+func showInvocations(functionName string) {
+	page := NewInvocationsPage(functionName)
+	ui.App.RegisterContent(page)
+	ui.App.ShowPage(page)
+}
+
+// NewInvocationsPage returns a page with a sparkline for each metric of the function. The metrics are read when
+// the page is rendered
+func NewInvocationsPage(functionName string) *InvocationsPage {
+	metrics := ui.NewMetricsView(1).
+		SetColor("Errors", tcell.ColorRed).
+		SetColor("Throttles", tcell.ColorOrange)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(metrics, 0, 1, false)
+	flex.SetBorder(true)
+
+	page := &InvocationsPage{
+		Flex:         flex,
+		metrics:      metrics,
+		functionName: functionName,
+		window:       ui.MetricWindows[0],
+	}
+
+	flex.SetInputCapture(page.handleInput)
+
+	return page
+}
+
+func (i *InvocationsPage) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
+	}
+
+	if window, found := ui.MetricWindowForKey(event.Rune()); found && window != i.window {
+		i.window = window
+		i.load()
+	}
+
+	return event
+}
+
+// load reads the metrics for the selected window and updates the sparklines. Errors are shown in the title, the
+// page is rendered while it is being switched to and a message box would be hidden
+func (i *InvocationsPage) load() {
+	series, err := aws.FetchLambdaMetrics(i.functionName, i.window.Duration)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read metrics of %s", i.functionName)
+		i.Flex.SetTitle(fmt.Sprintf(" 📈 Metrics of %s (failed to read, check log file) ", i.functionName))
+		return
+	}
+
+	title := fmt.Sprintf(" 📈 Metrics of %s, %s ", i.functionName, i.window.Label)
+	if rate, found := errorRate(series); found {
+		title = fmt.Sprintf(" 📈 Metrics of %s, %s, error rate %s ", i.functionName, i.window.Label, formatErrorRate(rate))
+	}
+	i.Flex.SetTitle(title)
+
+	i.metrics.SetSeries(series)
+}
+
+// errorRate returns the percentage of failed invocations, not found when the function was not invoked
+func errorRate(series []aws.MetricSeries) (float64, bool) {
+	invocations, errors := 0.0, 0.0
+	for _, s := range series {
+		for _, value := range s.Values {
+			switch s.Label {
+			case "Invocations":
+				invocations += value
+			case "Errors":
+				errors += value
+			}
+		}
+	}
+
+	if invocations == 0 {
+		return 0, false
+	}
+	return errors / invocations * 100, true
+}
+
+// formatErrorRate formats the percentage of failed invocations
+func formatErrorRate(rate float64) string {
+	return fmt.Sprintf("%.1f%%", rate)
+}
+
+func (*InvocationsPage) Name() string {
+	return "invocations"
+}
+
+func (i *InvocationsPage) Render(accountData *data.AccountData) {
+	i.load()
+}
+
+func (i *InvocationsPage) View() tview.Primitive {
+	return i.Flex
+}
+
+func (*InvocationsPage) Close() {
+}
+
+func (*InvocationsPage) IsPersistent() bool {
+	return false
+}
+
+func (i *InvocationsPage) SetFocus(app *tview.Application) {
+	app.SetFocus(i.Flex)
+}
+
+func (*InvocationsPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	bw := tw.BatchWriter()
+	defer bw.Close()
+
+	for _, window := range ui.MetricWindows {
+		fmt.Fprintf(bw, "[white::b]%c [darkcyan::-]%s\n", window.Key, window.Label)
+	}
+	fmt.Fprintln(bw, "[white::b]f [darkcyan::-]Functions")
+
+	return tw
+}
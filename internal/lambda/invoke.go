@@ -0,0 +1,226 @@
+package lambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/testevents"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+const noSavedEvent = "(none)"
+
+// invoke opens a dialog to edit a JSON payload and invoke the function with it. Payloads can be saved as test
+// events of the function and loaded again. The dialog stays open after an invocation, so the function can be
+// invoked again with a changed payload
+func invoke(functionName string) {
+	const INVOKE_DIALOG = "invoke_dialog"
+	pages := ui.App.Content
+	running := false
+
+	events, err := testevents.List(functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list saved events of %s", functionName)
+	}
+
+	form := tview.NewForm()
+	title := fmt.Sprintf(" ▶ Invoke %s ", functionName)
+
+	form.
+		AddDropDown("Saved event", append([]string{noSavedEvent}, events...), 0, nil).
+		AddTextArea("Payload", "{}", 0, 12, 0, nil).
+		AddCheckbox("Asynchronous", false, nil).
+		AddInputField("Save as", "", 30, nil, nil)
+
+	eventInput := form.GetFormItemByLabel("Saved event").(*tview.DropDown)
+	payloadInput := form.GetFormItemByLabel("Payload").(*tview.TextArea)
+	asyncInput := form.GetFormItemByLabel("Asynchronous").(*tview.Checkbox)
+	nameInput := form.GetFormItemByLabel("Save as").(*tview.InputField)
+
+	eventInput.SetSelectedFunc(func(name string, index int) {
+		if index <= 0 {
+			return
+		}
+
+		payload, err := testevents.Load(functionName, name)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to load event %s of %s", name, functionName)
+			ui.CreateMessageBox(fmt.Sprintf("Failed to load event %s, check log file", name))
+			return
+		}
+
+		payloadInput.SetText(string(payload), false)
+		nameInput.SetText(name)
+	})
+
+	form.
+		AddButton("Invoke", func() {
+			if running {
+				return
+			}
+
+			payload := []byte(strings.TrimSpace(payloadInput.GetText()))
+			if len(payload) > 0 && !json.Valid(payload) {
+				ui.CreateMessageBox("The payload is not valid JSON")
+				return
+			}
+
+			running = true
+			form.SetTitle(fmt.Sprintf(" ▶ Invoke %s (invoking...) ", functionName))
+			async := asyncInput.IsChecked()
+
+			go func() {
+				result, err := aws.InvokeLambdaFunction(functionName, payload, async)
+
+				ui.App.TviewApp.QueueUpdateDraw(func() {
+					running = false
+					form.SetTitle(title)
+
+					if err != nil {
+						log.Error().Err(err).Msgf("Failed to invoke %s", functionName)
+						ui.CreateMessageBox(fmt.Sprintf("Failed to invoke %s: %v", functionName, err))
+						return
+					}
+
+					showInvokeResult(functionName, result)
+				})
+			}()
+		}).
+		AddButton("Editor", func() {
+			ui.App.TviewApp.Suspend(func() {
+				payload, err := editInEditor(payloadInput.GetText())
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to edit payload")
+					return
+				}
+				payloadInput.SetText(payload, false)
+			})
+		}).
+		AddButton("Save", func() {
+			name := strings.TrimSpace(nameInput.GetText())
+
+			err := testevents.Save(functionName, name, []byte(payloadInput.GetText()))
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to save event %s of %s", name, functionName)
+				ui.CreateMessageBox(fmt.Sprintf("Failed to save event: %v", err))
+				return
+			}
+
+			events, _ := testevents.List(functionName)
+			options := append([]string{noSavedEvent}, events...)
+			eventInput.SetOptions(options, nil)
+			for i, option := range options {
+				if option == name {
+					eventInput.SetCurrentOption(i)
+				}
+			}
+			form.SetTitle(fmt.Sprintf(" ▶ Invoke %s (saved %s) ", functionName, name))
+		}).
+		AddButton("Close", func() {
+			pages.RemovePage(INVOKE_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(INVOKE_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(title).SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 100, 24, INVOKE_DIALOG)
+
+	pages.AddPage(INVOKE_DIALOG, modalPage, true, true)
+}
+
+// showInvokeResult shows the outcome of an invocation on top of the invoke dialog
+func showInvokeResult(functionName string, result *aws.InvokeResult) {
+	const RESULT_DIALOG = "invoke_result"
+	pages := ui.App.Content
+
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+
+	tw.SetBorder(true).SetTitle(fmt.Sprintf(" ▶ Result of %s ", functionName)).SetTitleAlign(tview.AlignLeft)
+
+	fmt.Fprintf(tw, "[white::b]Status code:[-::-] %d\n", result.StatusCode)
+	if result.FunctionError != "" {
+		fmt.Fprintf(tw, "[white::b]Function error:[-::-] [red]%s[-]\n", result.FunctionError)
+	}
+	if result.ExecutedVersion != "" {
+		fmt.Fprintf(tw, "[white::b]Executed version:[-::-] %s\n", result.ExecutedVersion)
+	}
+
+	if len(result.Payload) > 0 {
+		fmt.Fprintf(tw, "\n[white::b]Payload[-::-]\n%s\n", tview.Escape(formatPayload(result.Payload)))
+	}
+
+	if result.Log != "" {
+		fmt.Fprintf(tw, "\n[white::b]Log[-::-]\n%s", tview.Escape(result.Log))
+	}
+
+	tw.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape || key == tcell.KeyEnter {
+			pages.RemovePage(RESULT_DIALOG)
+		}
+	})
+
+	modalPage := ui.CreateModalPage(tw, nil, 120, 30, RESULT_DIALOG)
+
+	pages.AddPage(RESULT_DIALOG, modalPage, true, true)
+}
+
+// formatPayload indents a JSON payload, other payloads are returned as they are
+func formatPayload(payload []byte) string {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, payload, "", "  "); err != nil {
+		return string(payload)
+	}
+	return indented.String()
+}
+
+// editInEditor lets the user edit the text in $EDITOR, or vi if not set, and returns the edited text
+func editInEditor(text string) (string, error) {
+	file, err := os.CreateTemp("", "s9k-payload-*.json")
+	if err != nil {
+		return text, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return text, err
+	}
+	file.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// the editor may have arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return text, err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return text, err
+	}
+
+	return string(edited), nil
+}
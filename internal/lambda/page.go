@@ -2,11 +2,13 @@ package lambda
 
 import (
 	"fmt"
-	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 
+	"github.com/bsek/s9k/internal/aws"
 	"github.com/bsek/s9k/internal/data"
 	"github.com/bsek/s9k/internal/ui"
 	"github.com/bsek/s9k/internal/utils"
@@ -30,19 +32,61 @@ func NewLambdasPage() *LambdasPage {
 
 	lambdasTable.SetSelectable(true, false)
 
+	page := &LambdasPage{
+		name:  name,
+		table: lambdasTable,
+	}
+
 	lambdasTable.SetSelectedFunc(func(row, _ int) {
 		ref := lambdasTable.GetCell(row, 1).Reference.(data.Function)
 
-		createActionForm(*ref.FunctionName, *ref.LoggingConfig.LogGroup, ref.Architectures[0])
+		createActionForm(ref, page.reload)
 	})
 
-	return &LambdasPage{
-		name:  name,
-		table: lambdasTable,
+	lambdasTable.SetInputCapture(page.handleInput)
+
+	return page
+}
+
+// handleInput handles the concurrency shortcuts of the selected function
+func (l *LambdasPage) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune {
+		return event
 	}
+
+	row, _ := l.table.GetSelection()
+	function, found := l.table.GetCell(row, 1).Reference.(data.Function)
+	if !found {
+		return event
+	}
+
+	switch event.Rune() {
+	case 'x', 'X':
+		editConcurrency(function, l.reload)
+	case 't', 'T':
+		confirmThrottle(function, l.reload)
+	case 'r', 'R':
+		confirmRestore(function, l.reload)
+	}
+
+	return event
+}
+
+// reload reads the functions again after a change, the rest of the account data is kept
+func (l *LambdasPage) reload() {
+	functions, err := data.LoadFunctions()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reload lambda functions")
+		return
+	}
+
+	ui.App.AccountData.Functions = functions
+	l.Render(ui.App.AccountData)
 }
 
 func (l *LambdasPage) Render(accountData *data.AccountData) {
+	l.table.Clear()
+
 	lambdaData := accountData.Functions
 
 	if len(lambdaData) == 0 {
@@ -50,25 +94,27 @@ func (l *LambdasPage) Render(accountData *data.AccountData) {
 	}
 
 	data := lo.Map(lambdaData, func(function data.Function, _ int) []string {
-		modified, _ := time.Parse("2006-01-02T15:04:05.9Z0700", *function.LastModified)
 		return []string{
 			*function.FunctionName,
 			string(function.Runtime),
 			string(function.PackageType),
-			function.Tags["LastDeployed"],
+			function.Tags[aws.LastDeployedTag],
 			utils.FormatBytes(function.CodeSize),
 			utils.FormatBytes(((int64)(*function.MemorySize) * 1000000)),
 			fmt.Sprintf("%s s", utils.I32ToString(*function.Timeout)),
+			formatReservedConcurrency(function),
+			formatProvisionedConcurrency(function),
+			formatFunctionErrorRate(function),
 			string(function.Architectures[0]),
-			utils.FormatLocalDateTime(modified),
+			formatLastModified(function.LastModified),
 		}
 	})
 
 	data = ui.PrependRowNumColumn(data)
 
-	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignLeft, tview.AlignRight}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignRight, tview.AlignLeft, tview.AlignRight}
 	expansions := []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
-	headers := []string{"#", "Name ▾", "Runtime", "Package type", "Version", "Code size", "Memory size", "Timeout", "Architecture", "Last modified"}
+	headers := []string{"#", "Name ▾", "Runtime", "Package type", "Version", "Code size", "Memory size", "Timeout", "Reserved", "Provisioned", "Error rate", "Architecture", "Last modified"}
 
 	ui.AddTableData(l.table, headers, data, alignment, expansions, tview.Styles.PrimaryTextColor, true)
 
@@ -79,6 +125,14 @@ func (l *LambdasPage) Render(accountData *data.AccountData) {
 	}
 }
 
+// formatFunctionErrorRate returns the error rate of the last hour, empty when the function was not invoked
+func formatFunctionErrorRate(function data.Function) string {
+	if function.ErrorRate == nil {
+		return ""
+	}
+	return formatErrorRate(*function.ErrorRate)
+}
+
 func (l *LambdasPage) Name() string {
 	return l.name
 }
@@ -93,6 +147,9 @@ func (l *LambdasPage) ContextView() tview.Primitive {
 	defer bw.Close()
 
 	fmt.Fprintln(bw, "[white::b]Enter [darkcyan::-]Select")
+	fmt.Fprintln(bw, "[white::b]x [darkcyan::-]Concurrency")
+	fmt.Fprintln(bw, "[white::b]t [darkcyan::-]Throttle to zero")
+	fmt.Fprintln(bw, "[white::b]r [darkcyan::-]Restore concurrency")
 
 	return tw
 }
@@ -0,0 +1,411 @@
+package lambda
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/data"
+	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
+)
+
+var _ ui.ContentPage = (*VersionsPage)(nil)
+
+// VersionsPage lists the published versions and the aliases of a lambda function. New versions are published
+// from here, and aliases are pointed at a version, optionally sending a share of the traffic to a second version
+type VersionsPage struct {
+	Flex          *tview.Flex
+	versionsTable *tview.Table
+	aliasesTable  *tview.Table
+	functionName  string
+	versions      []lambdatypes.FunctionConfiguration
+	CurrentItem   int
+}
+
+// showVersions opens the versions page of a function
+func showVersions(functionName string) *VersionsPage {
+	page := NewVersionsPage(functionName)
+	ui.App.RegisterContent(page)
+	ui.App.ShowPage(page)
+	return page
+}
+
+// NewVersionsPage returns a page with the versions and aliases of the function. The data is read when the page
+// is rendered
+func NewVersionsPage(functionName string) *VersionsPage {
+	versionsTable := tview.NewTable().SetSelectable(true, false)
+	versionsTable.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" λ Versions of %s ", functionName))
+
+	aliasesTable := tview.NewTable().SetSelectable(true, false)
+	aliasesTable.
+		SetBorder(true).
+		SetTitle(" 🔀 Aliases ")
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(versionsTable, 0, 3, false).
+		AddItem(aliasesTable, 0, 1, false)
+
+	page := &VersionsPage{
+		Flex:          flex,
+		versionsTable: versionsTable,
+		aliasesTable:  aliasesTable,
+		functionName:  functionName,
+	}
+
+	aliasesTable.SetSelectedFunc(func(row, _ int) {
+		alias, found := aliasesTable.GetCell(row, 0).Reference.(lambdatypes.AliasConfiguration)
+		if found {
+			page.updateAlias(alias)
+		}
+	})
+
+	flex.SetInputCapture(page.handleInput)
+
+	return page
+}
+
+func (v *VersionsPage) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyTab {
+		v.CurrentItem = (v.CurrentItem + 1) % v.Flex.GetItemCount()
+		ui.App.TviewApp.SetFocus(v.Flex.GetItem(v.CurrentItem))
+	}
+
+	if event.Key() == tcell.KeyRune && (event.Rune() == 'n' || event.Rune() == 'N') {
+		v.publish("")
+	}
+
+	return event
+}
+
+// load reads the versions and aliases of the function and fills the tables. Errors are shown in the titles, the
+// page is rendered while it is being switched to and a message box would be hidden
+func (v *VersionsPage) load() {
+	versions, err := aws.ListLambdaVersions(v.functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list versions of %s", v.functionName)
+		v.versionsTable.SetTitle(fmt.Sprintf(" λ Versions of %s (failed to read, check log file) ", v.functionName))
+		return
+	}
+
+	aliases, err := aws.ListLambdaAliases(v.functionName)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to list aliases of %s", v.functionName)
+		v.aliasesTable.SetTitle(" 🔀 Aliases (failed to read, check log file) ")
+		return
+	}
+
+	v.versionsTable.SetTitle(fmt.Sprintf(" λ Versions of %s ", v.functionName))
+	v.aliasesTable.SetTitle(" 🔀 Aliases ")
+
+	v.versions = versions
+	v.renderVersions(versions, aliases)
+	v.renderAliases(aliases)
+}
+
+func (v *VersionsPage) renderVersions(versions []lambdatypes.FunctionConfiguration, aliases []lambdatypes.AliasConfiguration) {
+	v.versionsTable.Clear()
+
+	tableData := lo.Map(versions, func(version lambdatypes.FunctionConfiguration, _ int) []string {
+		return []string{
+			awssdk.ToString(version.Version),
+			awssdk.ToString(version.Description),
+			formatLastModified(version.LastModified),
+			utils.FormatBytes(version.CodeSize),
+			aliasesOfVersion(awssdk.ToString(version.Version), aliases),
+		}
+	})
+
+	headers := []string{"Version", "Description", "Last modified", "Code size", "Aliases"}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignRight, tview.AlignLeft}
+	expansions := []int{1, 3, 2, 1, 2}
+
+	ui.AddTableData(v.versionsTable, headers, tableData, alignment, expansions, tcell.ColorLightBlue, true)
+}
+
+func (v *VersionsPage) renderAliases(aliases []lambdatypes.AliasConfiguration) {
+	v.aliasesTable.Clear()
+
+	tableData := lo.Map(aliases, func(alias lambdatypes.AliasConfiguration, _ int) []string {
+		return []string{
+			awssdk.ToString(alias.Name),
+			awssdk.ToString(alias.FunctionVersion),
+			formatRouting(alias),
+			awssdk.ToString(alias.Description),
+		}
+	})
+
+	headers := []string{"Alias", "Version", "Routing", "Description"}
+	alignment := []int{tview.AlignLeft, tview.AlignLeft, tview.AlignLeft, tview.AlignLeft}
+	expansions := []int{1, 1, 3, 3}
+
+	ui.AddTableData(v.aliasesTable, headers, tableData, alignment, expansions, tcell.ColorGreenYellow, true)
+
+	// set reference to alias
+	for i, alias := range aliases {
+		v.aliasesTable.GetCell(i+1, 0).SetReference(alias)
+	}
+}
+
+// publish asks for a description and publishes the current code and configuration of the function as a new
+// version. Publishing waits for a pending update of the function, so it runs in the background
+func (v *VersionsPage) publish(description string) {
+	const PUBLISH_DIALOG = "publish_dialog"
+	pages := ui.App.Content
+
+	form := tview.NewForm().
+		AddInputField("Description", description, 50, nil, nil)
+
+	input := form.GetFormItemByLabel("Description").(*tview.InputField)
+
+	form.
+		AddButton("Publish", func() {
+			pages.RemovePage(PUBLISH_DIALOG)
+			v.doPublish(input.GetText())
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(PUBLISH_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(PUBLISH_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" Publish new version of %s ", v.functionName)).SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 70, 7, PUBLISH_DIALOG)
+
+	pages.AddPage(PUBLISH_DIALOG, modalPage, true, true)
+}
+
+func (v *VersionsPage) doPublish(description string) {
+	v.versionsTable.SetTitle(fmt.Sprintf(" λ Versions of %s (publishing...) ", v.functionName))
+
+	go func() {
+		version, err := aws.PublishLambdaVersion(v.functionName, description)
+
+		ui.App.TviewApp.QueueUpdateDraw(func() {
+			v.versionsTable.SetTitle(fmt.Sprintf(" λ Versions of %s ", v.functionName))
+
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to publish a version of %s", v.functionName)
+				ui.CreateMessageBox(fmt.Sprintf("Failed to publish a version of %s, check log file", v.functionName))
+				return
+			}
+
+			v.load()
+			ui.CreateMessageBox(fmt.Sprintf("Published version %s of %s", version, v.functionName))
+		})
+	}()
+}
+
+// updateAlias opens a dialog to point the alias at a version. A share of the traffic can be routed to a second
+// version, and increased step by step to shift traffic gradually before pointing the alias at the new version
+func (v *VersionsPage) updateAlias(alias lambdatypes.AliasConfiguration) {
+	const ALIAS_DIALOG = "alias_dialog"
+	pages := ui.App.Content
+	aliasName := awssdk.ToString(alias.Name)
+
+	versions := lo.Map(v.versions, func(version lambdatypes.FunctionConfiguration, _ int) string {
+		return awssdk.ToString(version.Version)
+	})
+	published := lo.Without(versions, aws.LatestVersion)
+	additional := append([]string{"none"}, published...)
+
+	current := awssdk.ToString(alias.FunctionVersion)
+	currentAdditional, currentWeight := "none", 0.0
+	if alias.RoutingConfig != nil {
+		for version, weight := range alias.RoutingConfig.AdditionalVersionWeights {
+			currentAdditional, currentWeight = version, weight
+		}
+	}
+
+	form := tview.NewForm().
+		AddTextView("Current", fmt.Sprintf("%s %s", current, formatRouting(alias)), 0, 1, false, false).
+		AddDropDown("Version", versions, max(slices.Index(versions, current), 0), nil).
+		AddDropDown("Shift traffic to", additional, max(slices.Index(additional, currentAdditional), 0), nil).
+		AddInputField("Weight %", strconv.Itoa(int(currentWeight*100)), 5, tview.InputFieldInteger, nil)
+
+	versionInput := form.GetFormItemByLabel("Version").(*tview.DropDown)
+	additionalInput := form.GetFormItemByLabel("Shift traffic to").(*tview.DropDown)
+	weightInput := form.GetFormItemByLabel("Weight %").(*tview.InputField)
+
+	form.
+		AddButton("Update", func() {
+			_, version := versionInput.GetCurrentOption()
+			_, additionalVersion := additionalInput.GetCurrentOption()
+
+			weights, err := parseRouting(version, additionalVersion, weightInput.GetText())
+			if err != nil {
+				ui.CreateMessageBox(err.Error())
+				return
+			}
+
+			text := fmt.Sprintf("Do you want to point alias %s of %s to version %s?", aliasName, v.functionName, version)
+			if len(weights) > 0 {
+				text = fmt.Sprintf("Do you want to point alias %s of %s to version %s, sending %s of the traffic to version %s?",
+					aliasName, v.functionName, version, formatWeight(weights[additionalVersion]), additionalVersion)
+			}
+
+			pages.RemovePage(ALIAS_DIALOG)
+			ui.CreateConfirmBox(text, func() {
+				v.doUpdateAlias(aliasName, version, weights)
+			}, func() {})
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(ALIAS_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(ALIAS_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(fmt.Sprintf(" Update alias %s ", aliasName)).SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 60, 13, ALIAS_DIALOG)
+
+	pages.AddPage(ALIAS_DIALOG, modalPage, true, true)
+}
+
+func (v *VersionsPage) doUpdateAlias(aliasName, version string, weights map[string]float64) {
+	err := aws.UpdateLambdaAlias(v.functionName, aliasName, version, weights)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to update alias %s of %s", aliasName, v.functionName)
+		ui.CreateMessageBox(fmt.Sprintf("Failed to update alias %s, check log file", aliasName))
+		return
+	}
+
+	v.load()
+	ui.CreateMessageBox(fmt.Sprintf("Alias %s of %s updated", aliasName, v.functionName))
+}
+
+// parseRouting returns the weight of the additional version, or an empty map if all traffic goes to the version
+func parseRouting(version, additionalVersion, weightText string) (map[string]float64, error) {
+	weights := map[string]float64{}
+	if additionalVersion == "none" {
+		return weights, nil
+	}
+
+	if version == aws.LatestVersion {
+		return nil, fmt.Errorf("traffic can only be shifted between published versions, not from %s", aws.LatestVersion)
+	}
+
+	if additionalVersion == version {
+		return nil, fmt.Errorf("traffic is shifted to the version the alias points at, choose another version")
+	}
+
+	weight, err := strconv.Atoi(weightText)
+	if err != nil || weight < 0 || weight >= 100 {
+		return nil, fmt.Errorf("%q is not a valid weight, it must be between 0 and 99 %%", weightText)
+	}
+
+	if weight > 0 {
+		weights[additionalVersion] = float64(weight) / 100
+	}
+
+	return weights, nil
+}
+
+// aliasesOfVersion lists the aliases routing traffic to a version together with their share of the traffic
+func aliasesOfVersion(version string, aliases []lambdatypes.AliasConfiguration) string {
+	names := []string{}
+	for _, alias := range aliases {
+		name := awssdk.ToString(alias.Name)
+		additional := 0.0
+		if alias.RoutingConfig != nil {
+			for v, weight := range alias.RoutingConfig.AdditionalVersionWeights {
+				additional += weight
+				if v == version {
+					names = append(names, fmt.Sprintf("%s (%s)", name, formatWeight(weight)))
+				}
+			}
+		}
+
+		if awssdk.ToString(alias.FunctionVersion) == version {
+			if additional > 0 {
+				name = fmt.Sprintf("%s (%s)", name, formatWeight(1-additional))
+			}
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatRouting describes the additional versions of an alias, e.g. 10% to 3
+func formatRouting(alias lambdatypes.AliasConfiguration) string {
+	if alias.RoutingConfig == nil {
+		return ""
+	}
+
+	weights := alias.RoutingConfig.AdditionalVersionWeights
+	routes := []string{}
+	for _, version := range slices.Sorted(maps.Keys(weights)) {
+		routes = append(routes, fmt.Sprintf("%s to %s", formatWeight(weights[version]), version))
+	}
+	return strings.Join(routes, ", ")
+}
+
+func formatWeight(weight float64) string {
+	return fmt.Sprintf("%.0f%%", weight*100)
+}
+
+// formatLastModified formats the last modified timestamp of a function, e.g. 2024-01-02T15:04:05.000+0000
+func formatLastModified(lastModified *string) string {
+	modified, err := time.Parse("2006-01-02T15:04:05.9Z0700", awssdk.ToString(lastModified))
+	if err != nil {
+		return awssdk.ToString(lastModified)
+	}
+	return utils.FormatLocalDateTime(modified)
+}
+
+func (*VersionsPage) Name() string {
+	return "versions"
+}
+
+func (v *VersionsPage) Render(accountData *data.AccountData) {
+	v.load()
+}
+
+func (v *VersionsPage) View() tview.Primitive {
+	return v.Flex
+}
+
+func (*VersionsPage) Close() {
+}
+
+func (*VersionsPage) IsPersistent() bool {
+	return false
+}
+
+func (v *VersionsPage) SetFocus(app *tview.Application) {
+	app.SetFocus(v.Flex.GetItem(v.CurrentItem))
+}
+
+func (*VersionsPage) ContextView() tview.Primitive {
+	tw := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(false).
+		SetWrap(false)
+
+	bw := tw.BatchWriter()
+	defer bw.Close()
+
+	fmt.Fprintln(bw, "[white::b]Tab [darkcyan::-]Select view")
+	fmt.Fprintln(bw, "")
+	fmt.Fprintln(bw, "[white::b]n [darkcyan::-]Publish version")
+	fmt.Fprintln(bw, "[white::b]Enter [darkcyan::-]Update alias")
+	fmt.Fprintln(bw, "[white::b]f [darkcyan::-]Functions")
+
+	return tw
+}
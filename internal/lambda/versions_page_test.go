@@ -0,0 +1,78 @@
+package lambda
+
+import (
+	"maps"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func TestParseRouting(t *testing.T) {
+	tests := []struct {
+		version, additionalVersion, weight string
+		want                               map[string]float64
+	}{
+		{"3", "none", "", map[string]float64{}},
+		{aws.LatestVersion, "none", "", map[string]float64{}},
+		{"3", "4", "10", map[string]float64{"4": 0.1}},
+		{"3", "4", "99", map[string]float64{"4": 0.99}},
+		{"3", "4", "0", map[string]float64{}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRouting(tt.version, tt.additionalVersion, tt.weight)
+		if err != nil {
+			t.Errorf("parseRouting(%q, %q, %q) failed: %v", tt.version, tt.additionalVersion, tt.weight, err)
+			continue
+		}
+		if !maps.Equal(got, tt.want) {
+			t.Errorf("parseRouting(%q, %q, %q) = %v, want %v", tt.version, tt.additionalVersion, tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoutingInvalid(t *testing.T) {
+	tests := []struct {
+		version, additionalVersion, weight string
+	}{
+		{aws.LatestVersion, "4", "10"},
+		{"3", "3", "10"},
+		{"3", "4", "100"},
+		{"3", "4", "-1"},
+		{"3", "4", "ten"},
+		{"3", "4", ""},
+	}
+
+	for _, tt := range tests {
+		if got, err := parseRouting(tt.version, tt.additionalVersion, tt.weight); err == nil {
+			t.Errorf("parseRouting(%q, %q, %q) = %v, want an error", tt.version, tt.additionalVersion, tt.weight, got)
+		}
+	}
+}
+
+func TestAliasesOfVersion(t *testing.T) {
+	aliases := []lambdatypes.AliasConfiguration{
+		{
+			Name:            awssdk.String("live"),
+			FunctionVersion: awssdk.String("3"),
+			RoutingConfig: &lambdatypes.AliasRoutingConfiguration{
+				AdditionalVersionWeights: map[string]float64{"4": 0.1},
+			},
+		},
+		{Name: awssdk.String("stable"), FunctionVersion: awssdk.String("3")},
+	}
+
+	tests := map[string]string{
+		"3": "live (90%), stable",
+		"4": "live (10%)",
+		"5": "",
+	}
+	for version, want := range tests {
+		if got := aliasesOfVersion(version, aliases); got != want {
+			t.Errorf("aliasesOfVersion(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+const FILTER_DIALOG = "log_filter_dialog"
+
+// showFilterDialog asks for a CloudWatch filter pattern for the live tail, and regular expressions the shown events
+// must match and must not match. Empty fields remove the filters
+func (l *LogPage) showFilterDialog() {
+	pages := ui.App.Content
+	p := l.logStreamPage
+
+	form := tview.NewForm().
+		AddInputField("Pattern", p.filterPattern, 40, nil, nil).
+		AddInputField("Include", regexpText(p.include), 40, nil, nil).
+		AddInputField("Exclude", regexpText(p.exclude), 40, nil, nil)
+
+	patternInput := form.GetFormItemByLabel("Pattern").(*tview.InputField)
+	includeInput := form.GetFormItemByLabel("Include").(*tview.InputField)
+	excludeInput := form.GetFormItemByLabel("Exclude").(*tview.InputField)
+
+	form.
+		AddButton("Apply", func() {
+			include, err := compileFilter(includeInput.GetText())
+			if err != nil {
+				ui.CreateMessageBox(fmt.Sprintf("Invalid include expression: %s", err))
+				return
+			}
+
+			exclude, err := compileFilter(excludeInput.GetText())
+			if err != nil {
+				ui.CreateMessageBox(fmt.Sprintf("Invalid exclude expression: %s", err))
+				return
+			}
+
+			// the pattern is checked by CloudWatch before the tail is restarted with it
+			pattern := strings.TrimSpace(patternInput.GetText())
+			if pattern != "" && pattern != p.filterPattern {
+				if err := aws.ValidateFilterPattern(p.LogGroupArn, pattern); err != nil {
+					var invalid *types.InvalidParameterException
+					if errors.As(err, &invalid) {
+						ui.CreateMessageBox(fmt.Sprintf("Invalid pattern: %s", aws.ErrorMessage(err)))
+						return
+					}
+					log.Error().Err(err).Msgf("Failed to check filter pattern %s", pattern)
+					ui.CreateMessageBox(fmt.Sprintf("Failed to check the pattern: %s", aws.ErrorMessage(err)))
+					return
+				}
+			}
+
+			pages.RemovePage(FILTER_DIALOG)
+			p.SetFilters(include, exclude)
+			p.SetFilterPattern(pattern)
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(FILTER_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(FILTER_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(" Filter events, the pattern restarts the tail ").SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 60, 11, FILTER_DIALOG)
+
+	pages.AddPage(FILTER_DIALOG, modalPage, true, true)
+}
+
+// compileFilter compiles a regular expression filter, nil when the text is empty
+func compileFilter(text string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	return regexp.Compile(text)
+}
+
+func regexpText(re *regexp.Regexp) string {
+	if re == nil {
+		return ""
+	}
+	return re.String()
+}
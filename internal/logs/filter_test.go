@@ -0,0 +1,66 @@
+package logs
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestCompileFilter(t *testing.T) {
+	for _, text := range []string{"", "   "} {
+		if re, err := compileFilter(text); re != nil || err != nil {
+			t.Errorf("compileFilter(%q) = %v, %v, want no filter", text, re, err)
+		}
+	}
+
+	re, err := compileFilter("ERROR|WARN")
+	if err != nil || regexpText(re) != "ERROR|WARN" {
+		t.Errorf("got filter %q and error %v, want ERROR|WARN", regexpText(re), err)
+	}
+
+	if _, err := compileFilter("level=(ERROR"); err == nil {
+		t.Error("compiling an invalid expression succeeded")
+	}
+
+	if got := regexpText(nil); got != "" {
+		t.Errorf("regexpText(nil) = %q, want empty", got)
+	}
+}
+
+func TestVisible(t *testing.T) {
+	events := []logEvent{
+		{timestamp: 1, message: "INFO Handled request /orders"},
+		{timestamp: 2, message: "ERROR Failed to send email"},
+		{timestamp: 3, message: "INFO Handled request /health"},
+		{timestamp: 4, message: "WARN Slow query"},
+	}
+
+	tests := []struct {
+		include string
+		exclude string
+		want    []int64
+	}{
+		{want: []int64{1, 2, 3, 4}},
+		{include: "Handled", want: []int64{1, 3}},
+		{exclude: "/health", want: []int64{1, 2, 4}},
+		{include: "INFO|WARN", exclude: "/health", want: []int64{1, 4}},
+	}
+
+	for _, test := range tests {
+		p := &LogStreamPage{}
+		if test.include != "" {
+			p.include = regexp.MustCompile(test.include)
+		}
+		if test.exclude != "" {
+			p.exclude = regexp.MustCompile(test.exclude)
+		}
+
+		got := make([]int64, 0)
+		for _, event := range p.visible(events) {
+			got = append(got, event.timestamp)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("include %q and exclude %q: got events %v, want %v", test.include, test.exclude, got, test.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package logs
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/gdamore/tcell/v2"
@@ -14,37 +15,52 @@ import (
 
 var _ ui.ContentPage = (*LogPage)(nil)
 
+// backfillKeys are the keys loading the events of a time range up to now
+var backfillKeys = map[rune]time.Duration{
+	'0': time.Minute,
+	'1': 5 * time.Minute,
+	'2': 15 * time.Minute,
+	'3': 30 * time.Minute,
+}
+
 type LogPage struct {
-	Flex            *tview.Flex
-	logStreamPage   *LogStreamPage
-	highlightField  *tview.InputField
-	closefunc       func()
-	logGroupArn     string
-	logStreams      []types.LogStream
-	highlightedText string
+	Flex          *tview.Flex
+	logStreamPage *LogStreamPage
+	searchField   *tview.InputField
+	closefunc     func()
+	logGroupArn   string
+	logStreams    []types.LogStream
+	// searchRegex and searchCase are the options of the search, toggled in the search field
+	searchRegex bool
+	searchCase  bool
 }
 
 func NewLogPage(logGroupArn string) *LogPage {
 	flex := tview.NewFlex()
 
 	logPage := &LogPage{
-		Flex:           flex,
-		logGroupArn:    logGroupArn,
-		highlightField: tview.NewInputField(),
+		Flex:        flex,
+		logGroupArn: logGroupArn,
+		searchField: tview.NewInputField(),
 	}
 
-	logPage.highlightField.SetChangedFunc(logPage.highlightTextChanged)
+	logPage.searchField.
+		SetChangedFunc(logPage.searchTextChanged).
+		SetDoneFunc(logPage.searchDone).
+		SetInputCapture(logPage.searchInputHandler)
+	logPage.updateSearchLabel()
 
 	flex.SetInputCapture(logPage.inputHandler)
 
 	return logPage
 }
 
-func (l *LogPage) highlightTextChanged(text string) {
-	l.logStreamPage.HighlightText(&text)
-}
-
 func (l *LogPage) inputHandler(event *tcell.EventKey) *tcell.EventKey {
+	// keys typed into the search field are not shortcuts
+	if l.searchField.HasFocus() {
+		return event
+	}
+
 	if event.Key() == tcell.KeyRune {
 
 		key := event.Rune()
@@ -56,6 +72,42 @@ func (l *LogPage) inputHandler(event *tcell.EventKey) *tcell.EventKey {
 		if key == 'w' || key == 'W' {
 			l.logStreamPage.SwitchWrap()
 		}
+
+		if since, found := backfillKeys[key]; found {
+			end := time.Now()
+			l.logStreamPage.Backfill(end.Add(-since), end, fmt.Sprintf("last %s", formatDuration(since)))
+		}
+
+		if key == 'r' || key == 'R' {
+			l.showRangeDialog()
+		}
+
+		if key == 'p' || key == 'P' {
+			l.logStreamPage.SwitchJson()
+			l.layout()
+			ui.App.TviewApp.SetFocus(l.logStreamPage.Primitive())
+		}
+
+		if key == 'o' || key == 'O' {
+			l.logStreamPage.ChooseFields()
+		}
+
+		if key == 'g' || key == 'G' {
+			l.showFilterDialog()
+		}
+
+		if key == '/' {
+			ui.App.TviewApp.SetFocus(l.searchField)
+			return nil
+		}
+
+		if key == 'n' {
+			l.logStreamPage.NextMatch(1)
+		}
+
+		if key == 'N' {
+			l.logStreamPage.NextMatch(-1)
+		}
 	}
 
 	return event
@@ -73,11 +125,18 @@ func (l *LogPage) buildUI() {
 	p := NewLogStreamPage(l.logGroupArn, true)
 	l.logStreamPage = p
 
+	l.layout()
+	// }
+}
+
+// layout shows the events as text or as a table of JSON fields above the search field
+func (l *LogPage) layout() {
+	l.Flex.Clear()
+
 	l.Flex.
 		SetDirection(tview.FlexRow).
-		AddItem(l.logStreamPage.View, 0, 1, false).
-		AddItem(l.highlightField, 2, 1, false)
-	// }
+		AddItem(l.logStreamPage.Primitive(), 0, 1, true).
+		AddItem(l.searchField, 2, 1, false)
 }
 
 func buildContextMenu() *tview.Flex {
@@ -94,13 +153,37 @@ func buildContextMenu() *tview.Flex {
 	fmt.Fprintln(bw, "[white::b]w [darkcyan::-]wrap")
 	fmt.Fprintln(bw, "[white::b]t [darkcyan::-]tail")
 	fmt.Fprintln(bw, "[white::b]p [darkcyan::-]parse json")
-	fmt.Fprintln(bw, "")
-	fmt.Fprintln(bw, "[white::b]0 [darkcyan::-]1m")
-	fmt.Fprintln(bw, "[white::b]1 [darkcyan::-]5m")
-	fmt.Fprintln(bw, "[white::b]2 [darkcyan::-]15m")
-	fmt.Fprintln(bw, "[white::b]3 [darkcyan::-]30m")
+	fmt.Fprintln(bw, "[white::b]o [darkcyan::-]json columns")
+	fmt.Fprintln(bw, "[white::b]g [darkcyan::-]filter")
+
+	// the time ranges are in a column of their own, the header has room for a few lines only
+	rangeBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
+	rw := rangeBar.BatchWriter()
+	defer rw.Close()
+
+	fmt.Fprintln(rw, "[white::b]0 [darkcyan::-]1m")
+	fmt.Fprintln(rw, "[white::b]1 [darkcyan::-]5m")
+	fmt.Fprintln(rw, "[white::b]2 [darkcyan::-]15m")
+	fmt.Fprintln(rw, "[white::b]3 [darkcyan::-]30m")
+	fmt.Fprintln(rw, "[white::b]r [darkcyan::-]range")
+
+	searchBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+
+	sw := searchBar.BatchWriter()
+	defer sw.Close()
+
+	fmt.Fprintln(sw, "[white::b]/ [darkcyan::-]search")
+	fmt.Fprintln(sw, "[white::b]n [darkcyan::-]next match")
+	fmt.Fprintln(sw, "[white::b]N [darkcyan::-]previous match")
 
 	flex.AddItem(configBar, 0, 1, false)
+	flex.AddItem(rangeBar, 0, 1, false)
+	flex.AddItem(searchBar, 0, 1, false)
 
 	return flex
 }
@@ -113,8 +196,12 @@ func (l *LogPage) Name() string {
 	return "logs"
 }
 
+// Render builds the page when it is shown the first time. The events and the live tail are kept when the account
+// data is updated, a new tail would be started without ending the current one
 func (l *LogPage) Render(accountData *data.AccountData) {
-	l.buildUI()
+	if l.logStreamPage == nil {
+		l.buildUI()
+	}
 }
 
 func (l *LogPage) SetFocus(app *tview.Application) {
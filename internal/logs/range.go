@@ -0,0 +1,104 @@
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"github.com/bsek/s9k/internal/ui"
+)
+
+const RANGE_DIALOG = "log_range_dialog"
+
+// showRangeDialog asks for a time range and backfills the events of the range. Times are durations before now,
+// e.g. 2h, or local times like 2024-01-02 15:04 or 15:04 for today. An empty end is now
+func (l *LogPage) showRangeDialog() {
+	pages := ui.App.Content
+
+	form := tview.NewForm().
+		AddInputField("From", "", 20, nil, nil).
+		AddInputField("To", "", 20, nil, nil)
+
+	fromInput := form.GetFormItemByLabel("From").(*tview.InputField)
+	toInput := form.GetFormItemByLabel("To").(*tview.InputField)
+
+	form.
+		AddButton("Load", func() {
+			now := time.Now()
+
+			start, err := parseRangeTime(fromInput.GetText(), now)
+			if err != nil {
+				ui.CreateMessageBox(err.Error())
+				return
+			}
+
+			end := now
+			if strings.TrimSpace(toInput.GetText()) != "" {
+				if end, err = parseRangeTime(toInput.GetText(), now); err != nil {
+					ui.CreateMessageBox(err.Error())
+					return
+				}
+			}
+
+			if !start.Before(end) {
+				ui.CreateMessageBox("The start of the range must be before the end")
+				return
+			}
+
+			pages.RemovePage(RANGE_DIALOG)
+			l.logStreamPage.Backfill(start, end, formatRange(start, end, now))
+		}).
+		AddButton("Cancel", func() {
+			pages.RemovePage(RANGE_DIALOG)
+		}).
+		SetCancelFunc(func() {
+			pages.RemovePage(RANGE_DIALOG)
+		})
+
+	form.SetBorder(true).SetTitle(" Load events from range, e.g. 2h or 15:04 ").SetTitleAlign(tview.AlignLeft)
+
+	modalPage := ui.CreateModalPage(form, nil, 60, 9, RANGE_DIALOG)
+
+	pages.AddPage(RANGE_DIALOG, modalPage, true, true)
+}
+
+// parseRangeTime parses a duration before now, a local date and time or a local time today
+func parseRangeTime(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+
+	if since, err := time.ParseDuration(text); err == nil && since > 0 {
+		return now.Add(-since), nil
+	}
+
+	if when, err := time.ParseInLocation("2006-01-02 15:04", text, time.Local); err == nil {
+		return when, nil
+	}
+
+	if when, err := time.ParseInLocation("15:04", text, time.Local); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), when.Hour(), when.Minute(), 0, 0, time.Local), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a duration like 2h, a time like 15:04 or a date and time like 2006-01-02 15:04", text)
+}
+
+// formatRange describes a backfilled range for the title of the log view
+func formatRange(start, end, now time.Time) string {
+	if end.Equal(now) {
+		return fmt.Sprintf("from %s", start.Format("2006-01-02 15:04"))
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+}
+
+// formatDuration formats a duration without zero units, e.g. 5m instead of 5m0s
+func formatDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
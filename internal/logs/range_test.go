@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRangeTime(t *testing.T) {
+	now := time.Date(2024, 5, 17, 14, 30, 15, 0, time.Local)
+
+	tests := map[string]time.Time{
+		"2h":               now.Add(-2 * time.Hour),
+		" 90m ":            now.Add(-90 * time.Minute),
+		"2024-05-16 08:15": time.Date(2024, 5, 16, 8, 15, 0, 0, time.Local),
+		"09:45":            time.Date(2024, 5, 17, 9, 45, 0, 0, time.Local),
+	}
+
+	for text, want := range tests {
+		got, err := parseRangeTime(text, now)
+		if err != nil {
+			t.Errorf("parseRangeTime(%q) failed: %v", text, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseRangeTime(%q) = %s, want %s", text, got, want)
+		}
+	}
+}
+
+func TestParseRangeTimeInvalid(t *testing.T) {
+	now := time.Date(2024, 5, 17, 14, 30, 15, 0, time.Local)
+
+	for _, text := range []string{"", "-2h", "0s", "yesterday", "25:00", "2024-05-16"} {
+		if got, err := parseRangeTime(text, now); err == nil {
+			t.Errorf("parseRangeTime(%q) = %s, want an error", text, got)
+		}
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	now := time.Date(2024, 5, 17, 14, 30, 15, 0, time.Local)
+	start := time.Date(2024, 5, 17, 9, 45, 0, 0, time.Local)
+
+	if got := formatRange(start, now, now); got != "from 2024-05-17 09:45" {
+		t.Errorf("got %q for a range until now", got)
+	}
+	if got := formatRange(start, start.Add(time.Hour), now); got != "2024-05-17 09:45 to 2024-05-17 10:45" {
+		t.Errorf("got %q for a closed range", got)
+	}
+}
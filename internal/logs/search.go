@@ -0,0 +1,181 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/bsek/s9k/internal/ui"
+)
+
+// logMatch is an occurrence of the search in the shown events, marked as a region of the text
+type logMatch struct {
+	region string
+	event  logEvent
+}
+
+// Search finds the occurrences of the text in the shown events and highlights the last one. The text is matched
+// as is unless regex is set. An empty text ends the search
+func (p *LogStreamPage) Search(text string, regex, ignoreCase bool) error {
+	p.search = nil
+
+	if text != "" {
+		if !regex {
+			text = regexp.QuoteMeta(text)
+		}
+		if ignoreCase {
+			text = "(?i)" + text
+		}
+
+		search, err := regexp.Compile(text)
+		if err != nil {
+			p.render()
+			p.setTitle()
+			return err
+		}
+		p.search = search
+	}
+
+	p.render()
+	p.match = len(p.matches) - 1
+	p.showMatch()
+
+	return nil
+}
+
+// NextMatch highlights the next occurrence of the search, or the previous one for a negative step. It wraps
+// around at the first and last occurrence
+func (p *LogStreamPage) NextMatch(step int) {
+	if len(p.matches) == 0 {
+		return
+	}
+
+	// without a current occurrence, the first step goes to the first or the last one
+	if p.match < 0 && step < 0 {
+		p.match = 0
+	}
+
+	p.match = ((p.match+step)%len(p.matches) + len(p.matches)) % len(p.matches)
+	p.showMatch()
+}
+
+// showMatch highlights the current occurrence, or scrolls to the end again when there is none
+func (p *LogStreamPage) showMatch() {
+	p.setTitle()
+
+	if p.match < 0 {
+		p.View.Highlight()
+		p.View.ScrollToEnd()
+		return
+	}
+
+	match := p.matches[p.match]
+	p.View.Highlight(match.region)
+	p.View.ScrollToHighlight()
+
+	if p.Json {
+		p.structured.selectEvent(match.event)
+	}
+}
+
+// formatEvent formats an event as one line with the timestamps highlighted and the occurrences of the search
+// marked as regions, the occurrences are added to the matches
+func (p *LogStreamPage) formatEvent(event logEvent) string {
+	message := stripNewLines(event.message)
+	if event.gap {
+		return formatGap(message)
+	}
+	if p.search == nil {
+		return highlightDateTime(tview.Escape(message))
+	}
+
+	var text strings.Builder
+	last := 0
+	for _, loc := range p.search.FindAllStringIndex(message, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+
+		region := fmt.Sprintf("m%d", p.nextRegion)
+		p.nextRegion++
+		p.matches = append(p.matches, logMatch{region: region, event: event})
+
+		fmt.Fprintf(&text, `%s["%s"]%s[""]`, highlightDateTime(tview.Escape(message[last:loc[0]])), region, tview.Escape(message[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	text.WriteString(highlightDateTime(tview.Escape(message[last:])))
+
+	return text.String()
+}
+
+// formatGap formats the marker of a gap in the live tail
+func formatGap(message string) string {
+	return fmt.Sprintf("[yellow::b]--- %s ---[-::-]", tview.Escape(message))
+}
+
+// dropMatches removes the occurrences in events dropped from the start of the shown events
+func (p *LogStreamPage) dropMatches(dropped []logEvent) {
+	gone := map[logEvent]bool{}
+	for _, event := range dropped {
+		gone[event] = true
+	}
+
+	count := 0
+	for count < len(p.matches) && gone[p.matches[count].event] {
+		count++
+	}
+
+	p.matches = p.matches[count:]
+	if p.match >= 0 {
+		p.match = max(p.match-count, 0)
+	}
+	if len(p.matches) == 0 {
+		p.match = -1
+	}
+}
+
+func (l *LogPage) searchTextChanged(text string) {
+	if err := l.logStreamPage.Search(text, l.searchRegex, !l.searchCase); err != nil {
+		l.searchField.SetFieldTextColor(tcell.ColorRed)
+		return
+	}
+	l.searchField.SetFieldTextColor(tview.Styles.PrimaryTextColor)
+}
+
+// searchDone returns to the events, Esc also ends the search
+func (l *LogPage) searchDone(key tcell.Key) {
+	if key == tcell.KeyEscape {
+		l.searchField.SetText("")
+	}
+	ui.App.TviewApp.SetFocus(l.logStreamPage.Primitive())
+}
+
+// searchInputHandler toggles the options of the search, Ctrl-R for regular expressions and Ctrl-T for matching case
+func (l *LogPage) searchInputHandler(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyCtrlR:
+		l.searchRegex = !l.searchRegex
+	case tcell.KeyCtrlT:
+		l.searchCase = !l.searchCase
+	default:
+		return event
+	}
+
+	l.updateSearchLabel()
+	l.searchTextChanged(l.searchField.GetText())
+	return nil
+}
+
+func (l *LogPage) updateSearchLabel() {
+	mode, matchCase := "text", "ignore case"
+	if l.searchRegex {
+		mode = "regex"
+	}
+	if l.searchCase {
+		matchCase = "match case"
+	}
+	l.searchField.SetLabel(fmt.Sprintf("Search (%s, %s): ", mode, matchCase))
+}
@@ -0,0 +1,101 @@
+package logs
+
+import "testing"
+
+func searchPage(t *testing.T) *LogStreamPage {
+	t.Helper()
+
+	p := NewLogStreamPage("/aws/lambda/email-sender", false)
+	p.appendEvents([]logEvent{
+		{timestamp: 1000, message: "INFO Handled request /orders"},
+		{timestamp: 2000, message: "ERROR Failed to send email, error: connection reset"},
+		{timestamp: 3000, message: "INFO Handled request /health"},
+	})
+	return p
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		text       string
+		regex      bool
+		ignoreCase bool
+		want       int
+	}{
+		{text: "Handled", want: 2},
+		{text: "error", want: 1},
+		{text: "error", ignoreCase: true, want: 2},
+		{text: "/orders|/health", want: 0},
+		{text: "/orders|/health", regex: true, want: 2},
+		{text: "", want: 0},
+	}
+
+	for _, test := range tests {
+		p := searchPage(t)
+
+		if err := p.Search(test.text, test.regex, test.ignoreCase); err != nil {
+			t.Errorf("Search(%q) failed: %v", test.text, err)
+			continue
+		}
+		if len(p.matches) != test.want {
+			t.Errorf("Search(%q, regex %t, ignore case %t) found %d matches, want %d", test.text, test.regex, test.ignoreCase, len(p.matches), test.want)
+		}
+		if p.match != test.want-1 {
+			t.Errorf("Search(%q) highlights match %d, want the last one", test.text, p.match)
+		}
+	}
+}
+
+func TestSearchInvalidExpression(t *testing.T) {
+	p := searchPage(t)
+
+	if err := p.Search("(orders", true, false); err == nil {
+		t.Error("searching for an invalid expression succeeded")
+	}
+	if p.search != nil || len(p.matches) != 0 {
+		t.Errorf("got %d matches after an invalid expression, want the search ended", len(p.matches))
+	}
+}
+
+func TestNextMatch(t *testing.T) {
+	p := searchPage(t)
+	if err := p.Search("INFO", false, false); err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+
+	steps := []struct {
+		step int
+		want int
+	}{
+		{step: 1, want: 0},
+		{step: 1, want: 1},
+		{step: -1, want: 0},
+		{step: -1, want: 1},
+	}
+	for _, s := range steps {
+		p.NextMatch(s.step)
+		if p.match != s.want {
+			t.Errorf("NextMatch(%d) highlights match %d, want %d", s.step, p.match, s.want)
+		}
+	}
+
+	if p.View.GetHighlights()[0] != p.matches[p.match].region {
+		t.Errorf("got highlights %v, want region %s", p.View.GetHighlights(), p.matches[p.match].region)
+	}
+}
+
+func TestDropMatches(t *testing.T) {
+	p := searchPage(t)
+	if err := p.Search("Handled", false, false); err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+
+	p.dropMatches(p.events[:1])
+	if len(p.matches) != 1 || p.match != 0 || p.matches[0].event.timestamp != 3000 {
+		t.Errorf("got %d matches with match %d, want the match in the last event", len(p.matches), p.match)
+	}
+
+	p.dropMatches(p.events)
+	if len(p.matches) != 0 || p.match != -1 {
+		t.Errorf("got %d matches with match %d, want none", len(p.matches), p.match)
+	}
+}
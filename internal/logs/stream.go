@@ -1,14 +1,12 @@
 package logs
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
-	"strings"
+	"sort"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
@@ -20,17 +18,45 @@ import (
 type LogStreamPage struct {
 	View        *tview.TextView
 	NextToken   *string
-	stream      *cloudwatchlogs.StartLiveTailEventStream
 	LogGroupArn string
-	ParseFields []string
 	//logStreams   []types.LogStream
 	wrap   bool
 	follow bool
-	Json   bool
+	// Json shows the events as a table of their JSON fields
+	Json       bool
+	structured *structuredView
+	// events are the shown events in timestamp order, kept so backfilled events can be merged in
+	events []logEvent
+	// backfill describes the backfilled time range, or the range being loaded
+	backfill string
+	// filterPattern is the CloudWatch filter pattern of the live tail and backfills
+	filterPattern string
+	// include and exclude filter the shown events, all events are kept
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+	// search is searched for in the shown events, matches are its occurrences and match the highlighted one
+	search     *regexp.Regexp
+	matches    []logMatch
+	match      int
+	nextRegion int
+	// stop stops the running live tail, tailError describes why it is disconnected
+	stop      chan struct{}
+	tailError string
+}
+
+// logEvent is a log event from the live tail or a backfill
+type logEvent struct {
+	timestamp int64
+	message   string
+	// gap marks where the live tail was disconnected
+	gap bool
 }
 
 const duration = 2 * time.Second
 
+// maxLines is the number of events kept in the view, older events are dropped
+const maxLines = 5000
+
 var (
 	timestampRe = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}Z)`)
 	newlineRe   = regexp.MustCompile(`\n`)
@@ -41,130 +67,317 @@ func NewLogStreamPage(logGroupArn string, load bool) *LogStreamPage {
 		SetDynamicColors(true).
 		SetWrap(false).
 		SetRegions(true).
-		SetMaxLines(400)
+		SetMaxLines(maxLines)
 
 	textView.SetBorder(true)
 
-	stream := openEventStream(logGroupArn)
-
 	page := LogStreamPage{
 		LogGroupArn: logGroupArn,
 		//	logStreams:   logStreams,
 		View:   textView,
 		wrap:   false,
-		follow: true,
-		stream: stream,
+		follow: load,
+		match:  -1,
 	}
 
 	if load {
-		go page.LoadData()
+		page.startTail()
 	}
 
 	return &page
 }
 
 func (p *LogStreamPage) End() {
-	if p.stream != nil {
-		p.stream.Close()
-	}
+	p.stopTail()
 }
 
 func (p *LogStreamPage) SwitchWrap() {
 	p.wrap = !p.wrap
 	p.View.SetWrap(p.wrap)
 
-	p.View.SetTitle(p.createTitle(p.View.GetOriginalLineCount()))
+	p.setTitle()
 }
 
-func (p *LogStreamPage) ParseFirstMessage() {
-	text := p.View.GetText(false)
-	lines := strings.Split(text, "\n")
+// SwitchJson switches between the events as text and as a table of their JSON fields. The fields shown as
+// columns are chosen from the shown events the first time
+func (p *LogStreamPage) SwitchJson() {
+	p.Json = !p.Json
 
-	if len(lines) > 0 {
-		var fieldsMap map[string]any
-		err := json.Unmarshal([]byte(lines[0]), &fieldsMap)
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to unmarshal log text")
-			return
+	if p.Json {
+		if p.structured == nil {
+			p.structured = newStructuredView()
+		}
+		if len(p.structured.fields) == 0 {
+			p.structured.fields = chooseDefaultFields(fieldKeys(p.events))
 		}
-		p.ParseFields = lo.Keys(fieldsMap)
+		p.structured.show(p.events)
+	}
+
+	p.setTitle()
+}
+
+// ChooseFields lets the fields shown as columns be picked from the fields of the shown events
+func (p *LogStreamPage) ChooseFields() {
+	if !p.Json {
+		return
+	}
+
+	p.structured.chooseFields(fieldKeys(p.events), func() {
+		p.structured.show(p.events)
+		ui.App.TviewApp.SetFocus(p.structured.table)
+	})
+}
+
+// Primitive returns the view of the events, the text or the table of JSON fields
+func (p *LogStreamPage) Primitive() tview.Primitive {
+	if p.Json {
+		return p.structured.Flex
 	}
+	return p.View
 }
 
+// SwitchFollow stops or starts the live tail
 func (p *LogStreamPage) SwitchFollow() {
 	p.follow = !p.follow
 	if p.follow {
-		p.stream.Close()
-		//	p.ticker.Reset(duration)
+		p.startTail()
 	} else {
-		p.stream = openEventStream(p.LogGroupArn)
-		go p.LoadData()
-		//	p.ticker.Stop()
-	}
-
-	p.View.SetTitle(p.createTitle(p.View.GetOriginalLineCount()))
-}
-
-func (p *LogStreamPage) HighlightText(text *string) {
-	p.View.Highlight(*text)
-}
-
-func (p *LogStreamPage) LoadData() {
-	eventsChan := p.stream.Events()
-	for {
-		event := <-eventsChan
-		switch e := event.(type) {
-		case *types.StartLiveTailResponseStreamMemberSessionStart:
-			log.Info().Msg("Received SessionStart event")
-		case *types.StartLiveTailResponseStreamMemberSessionUpdate:
-			log.Info().Msg("Received tail response")
-			ui.App.TviewApp.QueueUpdateDraw(func() {
-				bw := p.View.BatchWriter()
-				for _, logEvent := range e.Value.SessionResults {
-					fmt.Fprintln(bw, stripNewLines(highlightDateTime(*logEvent.Message)))
-				}
-				bw.Close()
-				p.View.ScrollToEnd()
-			})
-		default:
-			// Handle on-stream exceptions
-			if err := p.stream.Err(); err != nil {
-				log.Fatal().Err(err).Msg("Error occured during streaming")
-			} else if event == nil {
-				log.Info().Msg("Stream is Closed")
+		p.stopTail()
+	}
+
+	p.setTitle()
+}
+
+// appendEvents adds events from the live tail after the shown events
+func (p *LogStreamPage) appendEvents(events []logEvent) {
+	p.events = append(p.events, events...)
+	if len(p.events) > maxLines {
+		p.dropMatches(p.visible(p.events[:len(p.events)-maxLines]))
+		p.events = p.events[len(p.events)-maxLines:]
+	}
+
+	events = p.visible(events)
+
+	bw := p.View.BatchWriter()
+	for _, event := range events {
+		fmt.Fprintln(bw, p.formatEvent(event))
+	}
+	bw.Close()
+
+	// the view stays at the highlighted occurrence of a search
+	if p.match < 0 {
+		p.View.ScrollToEnd()
+	}
+
+	if p.Json {
+		// the columns are chosen when the first events arrive
+		if len(p.structured.fields) == 0 {
+			p.structured.fields = chooseDefaultFields(fieldKeys(p.events))
+			p.structured.show(p.visible(p.events))
+		} else {
+			p.structured.append(events)
+		}
+	}
+}
+
+// Backfill reads the events of the log group between start and end from all log streams, and merges them with
+// the shown events in timestamp order. The events are read in the background, the live tail continues meanwhile
+func (p *LogStreamPage) Backfill(start, end time.Time, description string) {
+	p.backfill = fmt.Sprintf("loading %s", description)
+	p.setTitle()
+
+	// the pattern may be changed while the events are read, the events are only shown if it is still the same
+	pattern := p.filterPattern
+
+	go func() {
+		filtered, truncated, err := aws.FilterLogEvents(p.LogGroupArn, start, end, pattern, maxLines)
+
+		ui.App.TviewApp.QueueUpdateDraw(func() {
+			if p.filterPattern != pattern {
+				return
+			}
+
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to load events of %s", p.LogGroupArn)
+				p.backfill = fmt.Sprintf("failed to load %s", description)
+				p.setTitle()
 				return
-			} else {
-				log.Error().Msgf("Unknown event type: %T", e)
 			}
+
+			events := make([]logEvent, 0, len(filtered))
+			for _, event := range filtered {
+				events = append(events, logEvent{timestamp: awssdk.ToInt64(event.Timestamp), message: awssdk.ToString(event.Message)})
+			}
+
+			p.mergeEvents(events)
+			p.backfill = description
+			if truncated {
+				p.backfill = fmt.Sprintf("%s, truncated, %s", description, describeTruncation(events))
+			}
+			p.setTitle()
+		})
+	}()
+}
+
+// describeTruncation tells up to when the events of a truncated backfill were read
+func describeTruncation(events []logEvent) string {
+	if len(events) == 0 {
+		return "no events read, load a shorter range"
+	}
+
+	last := time.UnixMilli(events[len(events)-1].timestamp)
+	return fmt.Sprintf("events after %s are missing, load a shorter range", last.Format("15:04:05"))
+}
+
+// mergeEvents merges the events with the shown events in timestamp order and shows them again. Events already
+// shown, e.g. received from the live tail during the backfilled range, are skipped
+func (p *LogStreamPage) mergeEvents(events []logEvent) {
+	shown := map[logEvent]bool{}
+	for _, event := range p.events {
+		shown[event] = true
+	}
+
+	for _, event := range events {
+		if !shown[event] {
+			p.events = append(p.events, event)
+			shown[event] = true
 		}
+	}
+
+	// the sort is stable, so events with the same timestamp keep the order they arrived in
+	sort.SliceStable(p.events, func(i, j int) bool {
+		return p.events[i].timestamp < p.events[j].timestamp
+	})
+	if len(p.events) > maxLines {
+		p.events = p.events[len(p.events)-maxLines:]
+	}
+
+	p.render()
+}
+
+// render shows the events passing the include and exclude filters again
+func (p *LogStreamPage) render() {
+	events := p.visible(p.events)
+
+	p.matches = nil
+	p.match = -1
+
+	p.View.Clear()
+	bw := p.View.BatchWriter()
+	for _, event := range events {
+		fmt.Fprintln(bw, p.formatEvent(event))
+	}
+	bw.Close()
+
+	p.View.ScrollToEnd()
+
+	if p.Json {
+		p.structured.show(events)
+	}
+}
+
+// visible returns the events matching the include filter and not matching the exclude filter
+func (p *LogStreamPage) visible(events []logEvent) []logEvent {
+	if p.include == nil && p.exclude == nil {
+		return events
+	}
+
+	// markers of gaps in the live tail are always shown
+	return lo.Filter(events, func(event logEvent, _ int) bool {
+		if event.gap {
+			return true
+		}
+		if p.include != nil && !p.include.MatchString(event.message) {
+			return false
+		}
+		return p.exclude == nil || !p.exclude.MatchString(event.message)
+	})
+}
+
+// SetFilterPattern restarts the live tail with a CloudWatch filter pattern. The shown events did not pass the
+// pattern and are dropped, a backfill has to be loaded again
+func (p *LogStreamPage) SetFilterPattern(pattern string) {
+	if pattern == p.filterPattern {
+		return
+	}
+	p.filterPattern = pattern
+
+	if p.follow {
+		p.stopTail()
+		p.startTail()
+	}
+
+	p.events = nil
+	p.backfill = ""
+	p.render()
+	p.setTitle()
+}
+
+// SetFilters sets the regular expressions the shown events must match and must not match, nil for no filter
+func (p *LogStreamPage) SetFilters(include, exclude *regexp.Regexp) {
+	p.include = include
+	p.exclude = exclude
+
+	p.render()
+	p.setTitle()
+}
+
+// setTitle shows the title on the text and the table of the events
+func (p *LogStreamPage) setTitle() {
+	title := p.createTitle(len(p.visible(p.events)))
 
-		p.View.SetTitle(p.createTitle(p.View.GetOriginalLineCount()))
+	p.View.SetTitle(title)
+	if p.structured != nil {
+		p.structured.table.SetTitle(title)
 	}
 }
 
 func (p *LogStreamPage) createTitle(length int) string {
 	title := fmt.Sprintf(" %s (%d rows", p.LogGroupArn, length)
 
+	if p.backfill != "" {
+		title = fmt.Sprintf(`%s, %s`, title, p.backfill)
+	}
+
+	if p.filterPattern != "" {
+		title = fmt.Sprintf(`%s, filter %s`, title, tview.Escape(p.filterPattern))
+	}
+	if p.include != nil {
+		title = fmt.Sprintf(`%s, include /%s/`, title, tview.Escape(p.include.String()))
+	}
+	if p.exclude != nil {
+		title = fmt.Sprintf(`%s, exclude /%s/`, title, tview.Escape(p.exclude.String()))
+	}
+
+	if p.search != nil {
+		switch {
+		case len(p.matches) == 0:
+			title = fmt.Sprintf(`%s, no matches`, title)
+		case p.match < 0:
+			title = fmt.Sprintf(`%s, %d matches`, title, len(p.matches))
+		default:
+			title = fmt.Sprintf(`%s, match %d/%d`, title, p.match+1, len(p.matches))
+		}
+	}
+
 	if p.follow {
 		title = fmt.Sprintf(`%s, tail`, title)
+		if p.tailError != "" {
+			title = fmt.Sprintf(`%s %s`, title, tview.Escape(p.tailError))
+		}
 	}
 	if p.wrap {
 		title = fmt.Sprintf(`%s, wrap`, title)
 	}
+	if p.Json {
+		title = fmt.Sprintf(`%s, json`, title)
+	}
 
 	title = fmt.Sprintf("%s) ", title)
 
 	return title
 }
 
-func openEventStream(logGroupArn string) *cloudwatchlogs.StartLiveTailEventStream {
-	stream, err := aws.FetchTailLogsChannel(logGroupArn)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to load log data")
-	}
-	return stream
-}
-
 // func (p *LogStreamPage) fetchLogItems() {
 // 	log.Debug().Msgf("Loading log data from %s", p.StreamName)
 // 	title := fmt.Sprintf(" %s (Loading...) ", p.StreamName)
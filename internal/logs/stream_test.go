@@ -0,0 +1,69 @@
+package logs
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeEvents(t *testing.T) {
+	p := NewLogStreamPage("/aws/lambda/email-sender", false)
+	p.appendEvents([]logEvent{
+		{timestamp: 3000, message: "tail 3"},
+		{timestamp: 5000, message: "tail 5"},
+	})
+
+	// the backfill overlaps with the live tail, the event at 3000 was received twice
+	p.mergeEvents([]logEvent{
+		{timestamp: 1000, message: "backfill 1"},
+		{timestamp: 3000, message: "tail 3"},
+		{timestamp: 3000, message: "backfill 3"},
+		{timestamp: 4000, message: "backfill 4"},
+	})
+
+	want := []logEvent{
+		{timestamp: 1000, message: "backfill 1"},
+		{timestamp: 3000, message: "tail 3"},
+		{timestamp: 3000, message: "backfill 3"},
+		{timestamp: 4000, message: "backfill 4"},
+		{timestamp: 5000, message: "tail 5"},
+	}
+	if !slices.Equal(p.events, want) {
+		t.Errorf("got %v, want %v", p.events, want)
+	}
+
+	if got := strings.Count(p.View.GetText(true), "\n"); got != len(want) {
+		t.Errorf("got %d lines in the view, want %d", got, len(want))
+	}
+}
+
+func TestMergeEventsKeepsNewest(t *testing.T) {
+	p := NewLogStreamPage("/aws/lambda/email-sender", false)
+	p.appendEvents([]logEvent{{timestamp: int64(maxLines + 1), message: "newest"}})
+
+	events := make([]logEvent, 0, maxLines)
+	for i := range maxLines {
+		events = append(events, logEvent{timestamp: int64(i), message: "backfill"})
+	}
+	p.mergeEvents(events)
+
+	if len(p.events) != maxLines {
+		t.Fatalf("got %d events, want %d", len(p.events), maxLines)
+	}
+	if p.events[0].timestamp != 1 || p.events[len(p.events)-1].message != "newest" {
+		t.Errorf("got events from %d to %q, want the oldest dropped", p.events[0].timestamp, p.events[len(p.events)-1].message)
+	}
+}
+
+func TestDescribeTruncation(t *testing.T) {
+	if got := describeTruncation(nil); got != "no events read, load a shorter range" {
+		t.Errorf("got %q without events", got)
+	}
+
+	last := time.Date(2024, 5, 17, 14, 30, 15, 0, time.Local)
+	events := []logEvent{{timestamp: last.Add(-time.Hour).UnixMilli()}, {timestamp: last.UnixMilli()}}
+	if got := describeTruncation(events); got != "events after 14:30:15 are missing, load a shorter range" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,304 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/ui"
+)
+
+const FIELDS_DIALOG = "log_fields_dialog"
+
+// defaultFields are the fields shown as columns when they are found in the events, in this order
+var defaultFields = []string{"timestamp", "@timestamp", "time", "level", "severity", "message", "msg", "traceId"}
+
+// messageFields are the fields holding the message, lines that are not JSON are shown in their column
+var messageFields = []string{"message", "msg"}
+
+// structuredView shows JSON log events as a table with a column for each chosen field, and the full object of the
+// selected event in a side pane. Lines that are not JSON are shown as they are
+type structuredView struct {
+	Flex   *tview.Flex
+	table  *tview.Table
+	detail *tview.TextView
+	fields []string
+}
+
+func newStructuredView() *structuredView {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true)
+
+	detail := tview.NewTextView().
+		SetDynamicColors(false).
+		SetWrap(true)
+	detail.SetBorder(true).SetTitle(" Event ")
+
+	flex := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(table, 0, 2, true).
+		AddItem(detail, 0, 1, false)
+
+	s := &structuredView{
+		Flex:   flex,
+		table:  table,
+		detail: detail,
+	}
+
+	table.SetSelectionChangedFunc(func(row, _ int) {
+		s.showDetail(row)
+	})
+
+	return s
+}
+
+// show replaces the rows of the table with the events
+func (s *structuredView) show(events []logEvent) {
+	s.table.Clear()
+
+	headers := s.fields
+	if len(headers) == 0 {
+		headers = []string{"message"}
+	}
+
+	alignment := make([]int, len(headers))
+	expansions := make([]int, len(headers))
+	for i := range headers {
+		alignment[i] = tview.AlignLeft
+		expansions[i] = 1
+	}
+	expansions[s.rawColumn(headers)] = 4
+
+	tableData := lo.Map(events, func(event logEvent, _ int) []string {
+		return s.row(event, headers)
+	})
+
+	ui.AddTableData(s.table, headers, tableData, alignment, expansions, tcell.ColorLightBlue, true)
+
+	for i, event := range events {
+		s.table.GetCell(i+1, 0).SetReference(event)
+	}
+
+	s.selectLast()
+}
+
+// append adds rows for events from the live tail and drops the oldest rows beyond maxLines. The selection moves
+// to the new events if the last row was selected
+func (s *structuredView) append(events []logEvent) {
+	selected, _ := s.table.GetSelection()
+	following := selected >= s.table.GetRowCount()-1
+
+	headers := s.fields
+	if len(headers) == 0 {
+		headers = []string{"message"}
+	}
+	rawColumn := s.rawColumn(headers)
+
+	for _, event := range events {
+		row := s.table.GetRowCount()
+		for col, text := range s.row(event, headers) {
+			expansion := 1
+			if col == rawColumn {
+				expansion = 4
+			}
+			s.table.SetCell(row, col, tview.NewTableCell(text).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(expansion).
+				SetTextColor(tcell.ColorLightBlue))
+		}
+		s.table.GetCell(row, 0).SetReference(event)
+	}
+
+	for s.table.GetRowCount()-1 > maxLines {
+		s.table.RemoveRow(1)
+	}
+
+	if following {
+		s.selectLast()
+	}
+}
+
+func (s *structuredView) selectLast() {
+	if last := s.table.GetRowCount() - 1; last > 0 {
+		s.table.Select(last, 0)
+		s.table.ScrollToEnd()
+	} else {
+		s.detail.Clear()
+	}
+}
+
+// selectEvent selects the row of the event
+func (s *structuredView) selectEvent(event logEvent) {
+	for row := 1; row < s.table.GetRowCount(); row++ {
+		if s.table.GetCell(row, 0).Reference == event {
+			s.table.Select(row, 0)
+			return
+		}
+	}
+}
+
+// row returns the cells of an event, the raw line is shown in the message column if it is not JSON
+func (s *structuredView) row(event logEvent, headers []string) []string {
+	cells := make([]string, len(headers))
+
+	if event.gap {
+		cells[s.rawColumn(headers)] = formatGap(event.message)
+		return cells
+	}
+
+	fields, isJson := parseJson(event.message)
+	if !isJson {
+		cells[s.rawColumn(headers)] = tview.Escape(stripNewLines(event.message))
+		return cells
+	}
+
+	for i, field := range headers {
+		if value, found := fields[field]; found {
+			cells[i] = formatField(field, value)
+		}
+	}
+	return cells
+}
+
+// rawColumn returns the column showing lines that are not JSON, the message column or else the last column
+func (s *structuredView) rawColumn(headers []string) int {
+	for i, field := range headers {
+		if slices.Contains(messageFields, field) {
+			return i
+		}
+	}
+	return len(headers) - 1
+}
+
+// showDetail pretty prints the event of the row in the side pane
+func (s *structuredView) showDetail(row int) {
+	s.detail.Clear()
+
+	event, found := s.table.GetCell(row, 0).Reference.(logEvent)
+	if !found {
+		return
+	}
+
+	var indented bytes.Buffer
+	if _, isJson := parseJson(event.message); isJson && json.Indent(&indented, []byte(event.message), "", "  ") == nil {
+		s.detail.SetText(indented.String())
+	} else {
+		s.detail.SetText(event.message)
+	}
+	s.detail.ScrollToBeginning()
+}
+
+// chooseFields lets the fields shown as columns be picked from the fields found in the events. Fields are shown in
+// the order they are picked
+func (s *structuredView) chooseFields(keys []string, done func()) {
+	pages := ui.App.Content
+
+	chosen := slices.Clone(s.fields)
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(" Columns, Enter to toggle, Esc when done ")
+
+	label := func(key string) string {
+		if slices.Contains(chosen, key) {
+			return fmt.Sprintf("[x] %s", key)
+		}
+		return fmt.Sprintf("[ ] %s", key)
+	}
+
+	for _, key := range keys {
+		list.AddItem(tview.Escape(label(key)), "", 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		key := keys[index]
+		if slices.Contains(chosen, key) {
+			chosen = slices.DeleteFunc(chosen, func(k string) bool { return k == key })
+		} else {
+			chosen = append(chosen, key)
+		}
+		list.SetItemText(index, tview.Escape(label(key)), "")
+	})
+
+	list.SetDoneFunc(func() {
+		pages.RemovePage(FIELDS_DIALOG)
+		s.fields = chosen
+		done()
+	})
+
+	modalPage := ui.CreateModalPage(list, nil, 50, min(len(keys), 20)+2, FIELDS_DIALOG)
+
+	pages.AddPage(FIELDS_DIALOG, modalPage, true, true)
+}
+
+// fieldKeys returns the fields found in the JSON events, sorted by name
+func fieldKeys(events []logEvent) []string {
+	keys := map[string]bool{}
+	for _, event := range events {
+		if fields, isJson := parseJson(event.message); isJson {
+			for key := range fields {
+				keys[key] = true
+			}
+		}
+	}
+
+	sorted := lo.Keys(keys)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// chooseDefaultFields returns the well known fields found in the keys, or the first few keys if there are none
+func chooseDefaultFields(keys []string) []string {
+	fields := lo.Filter(defaultFields, func(field string, _ int) bool {
+		return slices.Contains(keys, field)
+	})
+
+	if len(fields) == 0 {
+		fields = keys[:min(len(keys), 4)]
+	}
+	return fields
+}
+
+// parseJson parses a log line holding a JSON object
+func parseJson(message string) (map[string]any, bool) {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "{") {
+		return nil, false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// formatField formats the value of a field for a table cell, the level is colored
+func formatField(field string, value any) string {
+	text, isString := value.(string)
+	if !isString {
+		compact, err := json.Marshal(value)
+		if err != nil {
+			return ""
+		}
+		text = string(compact)
+	}
+	text = tview.Escape(stripNewLines(text))
+
+	if field == "level" || field == "severity" {
+		switch strings.ToUpper(text) {
+		case "ERROR", "FATAL", "CRITICAL":
+			return fmt.Sprintf("[red]%s", text)
+		case "WARN", "WARNING":
+			return fmt.Sprintf("[orange]%s", text)
+		}
+	}
+	return text
+}
@@ -0,0 +1,73 @@
+package logs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFieldKeys(t *testing.T) {
+	events := []logEvent{
+		{message: `{"timestamp":"2024-05-17T14:30:00Z","level":"INFO","message":"Handled request","status":200}`},
+		{message: `2024-05-17T14:30:01Z INFO Started background job`},
+		{message: `  {"level":"ERROR","error":"connection reset"}`},
+		{message: `{"broken":`},
+	}
+
+	want := []string{"error", "level", "message", "status", "timestamp"}
+	if got := fieldKeys(events); !slices.Equal(got, want) {
+		t.Errorf("got keys %v, want %v", got, want)
+	}
+}
+
+func TestChooseDefaultFields(t *testing.T) {
+	tests := []struct {
+		keys []string
+		want []string
+	}{
+		{keys: []string{"error", "level", "message", "status", "timestamp"}, want: []string{"timestamp", "level", "message"}},
+		{keys: []string{"a", "b", "c", "d", "e"}, want: []string{"a", "b", "c", "d"}},
+		{keys: []string{"a"}, want: []string{"a"}},
+		{keys: []string{}, want: []string{}},
+	}
+
+	for _, test := range tests {
+		if got := chooseDefaultFields(test.keys); !slices.Equal(got, test.want) {
+			t.Errorf("chooseDefaultFields(%v) = %v, want %v", test.keys, got, test.want)
+		}
+	}
+}
+
+func TestParseJson(t *testing.T) {
+	fields, isJson := parseJson(` {"level":"INFO","durationMs":1250} `)
+	if !isJson || fields["level"] != "INFO" || fields["durationMs"] != 1250.0 {
+		t.Errorf("got %v, %t, want the fields of the object", fields, isJson)
+	}
+
+	for _, message := range []string{"INFO Started background job", `["a","b"]`, `{"level":`, ""} {
+		if _, isJson := parseJson(message); isJson {
+			t.Errorf("parseJson(%q) returned a JSON object", message)
+		}
+	}
+}
+
+func TestFormatField(t *testing.T) {
+	tests := []struct {
+		field string
+		value any
+		want  string
+	}{
+		{field: "message", value: "Handled request", want: "Handled request"},
+		{field: "status", value: 200.0, want: "200"},
+		{field: "context", value: map[string]any{"id": 42.0}, want: `{"id":42}`},
+		{field: "level", value: "error", want: "[red]error"},
+		{field: "severity", value: "WARNING", want: "[orange]WARNING"},
+		{field: "level", value: "INFO", want: "INFO"},
+		{field: "message", value: "failed [retry]", want: "failed [retry[]"},
+	}
+
+	for _, test := range tests {
+		if got := formatField(test.field, test.value); got != test.want {
+			t.Errorf("formatField(%s, %v) = %q, want %q", test.field, test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/ui"
+)
+
+// minBackoff and maxBackoff are the first and the longest wait before a live tail session is started again
+const (
+	minBackoff = 2 * time.Second
+	maxBackoff = time.Minute
+)
+
+// errSessionEnded is returned when CloudWatch ends a live tail session without an error
+var errSessionEnded = errors.New("live tail session ended")
+
+// startTail starts the live tail in the background, it runs until stopTail is called
+func (p *LogStreamPage) startTail() {
+	stop := make(chan struct{})
+	p.stop = stop
+	p.tailError = ""
+
+	go p.tail(stop, p.filterPattern)
+}
+
+// stopTail stops the live tail and closes its session
+func (p *LogStreamPage) stopTail() {
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	p.tailError = ""
+}
+
+// tail reads live tail sessions until stopped. Sessions end after a few hours, and fail on throttling or network
+// errors, they are started again with a growing backoff. A marker is shown where events may be missing. Errors a new
+// session would fail with as well end the tail
+func (p *LogStreamPage) tail(stop chan struct{}, filterPattern string) {
+	backoff := minBackoff
+	// disconnected is when the events stopped arriving, zero while no session has failed
+	var disconnected time.Time
+
+	for {
+		stream, err := aws.FetchTailLogsChannel(p.LogGroupArn, filterPattern)
+		if err == nil {
+			var started bool
+			started, err = p.readSession(stream, stop, disconnected)
+			if err == nil {
+				return
+			}
+			if started {
+				// the next session is started without waiting long, a session is rarely throttled once it is up
+				disconnected = time.Now()
+				backoff = minBackoff
+			}
+		}
+		if disconnected.IsZero() {
+			disconnected = time.Now()
+		}
+
+		// a session fails the same way again when the log group is missing, the pattern is invalid or access is
+		// denied, the tail is stopped until it is resumed with t
+		if !retryable(err) {
+			log.Error().Err(err).Msgf("Live tail of %s failed, not retrying", p.LogGroupArn)
+
+			message := fmt.Sprintf("stopped: %s, press t twice to retry", describeTailError(err))
+			p.queueUpdate(stop, func() {
+				p.tailError = message
+				p.setTitle()
+			})
+			return
+		}
+
+		if errors.Is(err, errSessionEnded) {
+			log.Info().Msgf("Live tail session of %s ended, starting a new session in %s", p.LogGroupArn, backoff)
+		} else {
+			log.Error().Err(err).Msgf("Live tail of %s failed, retrying in %s", p.LogGroupArn, backoff)
+		}
+
+		message := fmt.Sprintf("disconnected: %s, reconnecting in %s", describeTailError(err), backoff)
+		p.queueUpdate(stop, func() {
+			p.tailError = message
+			p.setTitle()
+		})
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// readSession shows the events of a live tail session until it is stopped, nil is returned, or the session ends.
+// It returns whether CloudWatch started the session. If the tail was disconnected, a marker is shown when the
+// session starts
+func (p *LogStreamPage) readSession(stream *cloudwatchlogs.StartLiveTailEventStream, stop chan struct{}, disconnected time.Time) (bool, error) {
+	defer stream.Close()
+
+	started := false
+	events := stream.Events()
+	for {
+		select {
+		case <-stop:
+			return started, nil
+		case event, ok := <-events:
+			if !ok || event == nil {
+				if err := stream.Err(); err != nil {
+					return started, err
+				}
+				return started, errSessionEnded
+			}
+
+			switch e := event.(type) {
+			case *types.StartLiveTailResponseStreamMemberSessionStart:
+				log.Info().Msg("Received SessionStart event")
+				started = true
+				reconnected := time.Now()
+				p.queueUpdate(stop, func() {
+					if !disconnected.IsZero() {
+						p.markGap(disconnected, reconnected)
+					}
+					p.tailError = ""
+					p.setTitle()
+				})
+			case *types.StartLiveTailResponseStreamMemberSessionUpdate:
+				log.Info().Msg("Received tail response")
+				p.queueUpdate(stop, func() {
+					events := make([]logEvent, 0, len(e.Value.SessionResults))
+					for _, result := range e.Value.SessionResults {
+						events = append(events, logEvent{timestamp: awssdk.ToInt64(result.Timestamp), message: awssdk.ToString(result.Message)})
+					}
+					p.appendEvents(events)
+					p.setTitle()
+				})
+			default:
+				log.Error().Msgf("Unknown event type: %T", e)
+			}
+		}
+	}
+}
+
+// queueUpdate updates the page unless the tail has been stopped or started again meanwhile
+func (p *LogStreamPage) queueUpdate(stop chan struct{}, update func()) {
+	ui.App.TviewApp.QueueUpdateDraw(func() {
+		if p.stop == stop {
+			update()
+		}
+	})
+}
+
+// markGap adds a marker where the live tail was disconnected, events from the time range may be missing
+func (p *LogStreamPage) markGap(from, to time.Time) {
+	message := fmt.Sprintf("reconnected, events from %s to %s may be missing, press 0-3 or r to load them",
+		from.Format("15:04:05"), to.Format("15:04:05"))
+
+	p.appendEvents([]logEvent{{timestamp: from.UnixMilli(), message: message, gap: true}})
+}
+
+// retryable tells whether a new live tail session may succeed after the error
+func retryable(err error) bool {
+	var (
+		invalid  *types.InvalidParameterException
+		notFound *types.ResourceNotFoundException
+		denied   *types.AccessDeniedException
+	)
+
+	return !errors.As(err, &invalid) && !errors.As(err, &notFound) && !errors.As(err, &denied)
+}
+
+// describeTailError returns a short description of why a live tail session ended, the message from AWS if the
+// error is not one of the expected ones
+func describeTailError(err error) string {
+	var (
+		timeout     *types.SessionTimeoutException
+		streaming   *types.SessionStreamingException
+		throttling  *types.ThrottlingException
+		limit       *types.LimitExceededException
+		unavailable *types.ServiceUnavailableException
+	)
+
+	switch {
+	case errors.Is(err, errSessionEnded):
+		return "session ended"
+	case errors.As(err, &timeout):
+		return "session timed out"
+	case errors.As(err, &streaming):
+		return "session interrupted"
+	case errors.As(err, &throttling):
+		return "throttled"
+	case errors.As(err, &limit):
+		return "too many live tail sessions"
+	case errors.As(err, &unavailable):
+		return "service unavailable"
+	default:
+		return aws.ErrorMessage(err)
+	}
+}
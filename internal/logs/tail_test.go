@@ -0,0 +1,50 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errSessionEnded, true},
+		{errors.New("connection reset by peer"), true},
+		{&types.ThrottlingException{}, true},
+		{&types.SessionTimeoutException{}, true},
+		{fmt.Errorf("operation error: %w", &types.InvalidParameterException{}), false},
+		{fmt.Errorf("operation error: %w", &types.ResourceNotFoundException{}), false},
+		{fmt.Errorf("operation error: %w", &types.AccessDeniedException{}), false},
+	}
+
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("retryable(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeTailError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errSessionEnded, "session ended"},
+		{fmt.Errorf("operation error: %w", &types.ThrottlingException{}), "throttled"},
+		{&types.LimitExceededException{}, "too many live tail sessions"},
+		{&types.AccessDeniedException{Message: awssdk.String("not authorized to perform logs:StartLiveTail")}, "not authorized to perform logs:StartLiveTail"},
+		{errors.New("connection reset by peer"), "connection reset by peer"},
+	}
+
+	for _, tt := range tests {
+		if got := describeTailError(tt.err); got != tt.want {
+			t.Errorf("describeTailError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
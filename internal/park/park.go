@@ -0,0 +1,205 @@
+// Package park scales every service in an ECS cluster to zero and back. The desired count of each service, and the
+// minimum capacity of services in Application Auto Scaling, are saved in tags on the service before it is scaled
+// down, so a cluster parked on one machine can be unparked on another
+package park
+
+import (
+	"fmt"
+	"strconv"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/samber/lo"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+// DesiredCountTag is the service tag holding the desired count of a parked service
+const DesiredCountTag = "s9k:parked-desired-count"
+
+// MinCapacityTag is the service tag holding the auto scaling minimum capacity of a parked service. The minimum is
+// set to 0 while the service is parked, otherwise auto scaling would start its tasks again
+const MinCapacityTag = "s9k:parked-min-capacity"
+
+// Statuses of a service after parking or unparking
+const (
+	StatusParked   = "parked"
+	StatusUnparked = "unparked"
+	StatusSkipped  = "skipped"
+	StatusFailed   = "failed"
+)
+
+// Result is the outcome of parking or unparking a single service
+type Result struct {
+	Service string
+	Before  int32
+	After   int32
+	Status  string
+	Message string
+}
+
+// Park saves the desired count of every service in the cluster and scales the services to zero. Progress is called
+// with the result of each service as it completes. An error is returned if the services could not be read
+func Park(clusterName string, progress func(Result)) error {
+	services, err := aws.DescribeClusterServices(clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to read services in cluster %s: %w", clusterName, err)
+	}
+
+	for _, service := range services {
+		progress(parkService(clusterName, service))
+	}
+
+	return nil
+}
+
+// Unpark scales every parked service in the cluster back to its saved desired count and removes the saved count.
+// Progress is called with the result of each service as it completes
+func Unpark(clusterName string, progress func(Result)) error {
+	services, err := aws.DescribeClusterServices(clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to read services in cluster %s: %w", clusterName, err)
+	}
+
+	for _, service := range services {
+		progress(unparkService(clusterName, service))
+	}
+
+	return nil
+}
+
+// SavedDesiredCount returns the desired count saved on a parked service
+func SavedDesiredCount(service types.Service) (int32, bool, error) {
+	return savedCount(service, DesiredCountTag, "desired count")
+}
+
+// SavedMinCapacity returns the auto scaling minimum capacity saved on a parked service
+func SavedMinCapacity(service types.Service) (int32, bool, error) {
+	return savedCount(service, MinCapacityTag, "minimum capacity")
+}
+
+func savedCount(service types.Service, key, name string) (int32, bool, error) {
+	tag, found := lo.Find(service.Tags, func(t types.Tag) bool {
+		return awssdk.ToString(t.Key) == key
+	})
+	if !found {
+		return 0, false, nil
+	}
+
+	count, err := strconv.ParseInt(awssdk.ToString(tag.Value), 10, 32)
+	if err != nil || count < 0 {
+		return 0, true, fmt.Errorf("invalid saved %s %q", name, awssdk.ToString(tag.Value))
+	}
+
+	return int32(count), true, nil
+}
+
+func parkService(clusterName string, service types.Service) Result {
+	name := awssdk.ToString(service.ServiceName)
+	result := Result{Service: name, Before: service.DesiredCount, After: service.DesiredCount}
+
+	if saved, found, _ := SavedDesiredCount(service); found {
+		result.Status = StatusSkipped
+		result.Message = fmt.Sprintf("already parked with %d desired tasks", saved)
+		return result
+	}
+
+	if service.DesiredCount == 0 {
+		result.Status = StatusSkipped
+		result.Message = "no desired tasks"
+		return result
+	}
+
+	// a minimum capacity in auto scaling would start the tasks again, it is saved and set to 0 as well
+	limits, err := aws.FetchServiceScalingLimits(clusterName, name)
+	if err != nil {
+		return failed(result, "failed to read auto scaling limits", err)
+	}
+
+	tags := map[string]string{DesiredCountTag: strconv.Itoa(int(service.DesiredCount))}
+	if limits != nil && limits.Min > 0 {
+		tags[MinCapacityTag] = strconv.Itoa(int(limits.Min))
+	}
+
+	// the counts are saved first, a service is never scaled down without a way back
+	if err := aws.TagECSResource(*service.ServiceArn, tags); err != nil {
+		return failed(result, "failed to save desired count", err)
+	}
+
+	if _, found := tags[MinCapacityTag]; found {
+		if err := aws.SetServiceMinCapacity(clusterName, name, 0); err != nil {
+			return untagAfterFailure(result, service, "failed to set auto scaling minimum capacity to 0", err)
+		}
+		result.Message = fmt.Sprintf("auto scaling minimum capacity %d set to 0", limits.Min)
+	}
+
+	if err := aws.ScaleECSService(clusterName, name, 0); err != nil {
+		if _, found := tags[MinCapacityTag]; found {
+			if minErr := aws.SetServiceMinCapacity(clusterName, name, limits.Min); minErr != nil {
+				return failed(result, "failed to scale to 0 and to restore the auto scaling minimum capacity", err)
+			}
+		}
+		return untagAfterFailure(result, service, "failed to scale to 0", err)
+	}
+
+	result.After = 0
+	result.Status = StatusParked
+
+	return result
+}
+
+// untagAfterFailure removes the saved counts from a service that could not be parked, so it is not seen as parked
+func untagAfterFailure(result Result, service types.Service, message string, err error) Result {
+	if untagErr := aws.UntagECSResource(*service.ServiceArn, DesiredCountTag, MinCapacityTag); untagErr != nil {
+		return failed(result, message+" and to remove the saved desired count", err)
+	}
+	return failed(result, message, err)
+}
+
+func unparkService(clusterName string, service types.Service) Result {
+	name := awssdk.ToString(service.ServiceName)
+	result := Result{Service: name, Before: service.DesiredCount, After: service.DesiredCount}
+
+	saved, found, err := SavedDesiredCount(service)
+	if !found {
+		result.Status = StatusSkipped
+		result.Message = "not parked"
+		return result
+	}
+	if err != nil {
+		return failed(result, "failed to read saved desired count", err)
+	}
+
+	savedMin, minFound, err := SavedMinCapacity(service)
+	if err != nil {
+		return failed(result, "failed to read saved minimum capacity", err)
+	}
+
+	// the minimum capacity is restored first, the saved desired count is within the limits again
+	if minFound {
+		if err := aws.SetServiceMinCapacity(clusterName, name, savedMin); err != nil {
+			return failed(result, fmt.Sprintf("failed to restore auto scaling minimum capacity %d", savedMin), err)
+		}
+		result.Message = fmt.Sprintf("auto scaling minimum capacity restored to %d", savedMin)
+	}
+
+	if err := aws.ScaleECSService(clusterName, name, saved); err != nil {
+		return failed(result, fmt.Sprintf("failed to scale to %d", saved), err)
+	}
+
+	result.After = saved
+
+	if err := aws.UntagECSResource(*service.ServiceArn, DesiredCountTag, MinCapacityTag); err != nil {
+		return failed(result, "scaled up, but failed to remove the saved desired count", err)
+	}
+
+	result.Status = StatusUnparked
+
+	return result
+}
+
+func failed(result Result, message string, err error) Result {
+	result.Status = StatusFailed
+	result.Message = fmt.Sprintf("%s: %v", message, err)
+	return result
+}
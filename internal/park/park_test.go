@@ -0,0 +1,181 @@
+package park
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/aws/fake"
+)
+
+// collect runs the operation on the cluster and returns the results by service name
+func collect(t *testing.T, operation func(string, func(Result)) error, clusterName string) map[string]Result {
+	t.Helper()
+
+	results := make(map[string]Result)
+	if err := operation(clusterName, func(r Result) { results[r.Service] = r }); err != nil {
+		t.Fatalf("failed to run on cluster %s: %v", clusterName, err)
+	}
+	return results
+}
+
+// services returns the services of the cluster by name
+func services(t *testing.T, clusterName string) map[string]types.Service {
+	t.Helper()
+
+	list, err := aws.DescribeClusterServices(clusterName)
+	if err != nil {
+		t.Fatalf("failed to describe the services: %v", err)
+	}
+
+	services := make(map[string]types.Service)
+	for _, service := range list {
+		services[awssdk.ToString(service.ServiceName)] = service
+	}
+	return services
+}
+
+func TestPark(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	results := collect(t, Park, "test")
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	for name, service := range services(t, "test") {
+		result := results[name]
+		if result.Status != StatusParked || result.Before != 2 || result.After != 0 {
+			t.Errorf("got result %+v for %s, want parked from 2 to 0", result, name)
+		}
+		if service.DesiredCount != 0 {
+			t.Errorf("got desired count %d for %s, want 0", service.DesiredCount, name)
+		}
+		if saved, found, err := SavedDesiredCount(service); !found || err != nil || saved != 2 {
+			t.Errorf("got saved desired count %d, %t, %v for %s, want 2", saved, found, err, name)
+		}
+	}
+
+	for name, result := range collect(t, Park, "test") {
+		if result.Status != StatusSkipped {
+			t.Errorf("got status %s for %s when parked twice, want skipped", result.Status, name)
+		}
+	}
+}
+
+func TestParkSetsMinCapacityToZero(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	results := collect(t, Park, "test")
+	if results["api"].Message != "auto scaling minimum capacity 2 set to 0" {
+		t.Errorf("got message %q for api", results["api"].Message)
+	}
+
+	limits, err := aws.FetchServiceScalingLimits("test", "api")
+	if err != nil {
+		t.Fatalf("failed to read the limits: %v", err)
+	}
+	if limits == nil || limits.Min != 0 {
+		t.Errorf("got limits %+v for a parked service, want min 0", limits)
+	}
+
+	api := services(t, "test")["api"]
+	if saved, found, err := SavedMinCapacity(api); !found || err != nil || saved != 2 {
+		t.Errorf("got saved minimum capacity %d, %t, %v, want 2", saved, found, err)
+	}
+	if _, found, _ := SavedMinCapacity(services(t, "test")["web"]); found {
+		t.Error("a minimum capacity was saved for a service without auto scaling")
+	}
+}
+
+func TestParkSkipsServicesWithoutTasks(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	if err := aws.ScaleECSService("test", "worker", 0); err != nil {
+		t.Fatalf("failed to scale the service: %v", err)
+	}
+
+	results := collect(t, Park, "test")
+	if results["worker"].Status != StatusSkipped {
+		t.Errorf("got status %s for a service without tasks, want skipped", results["worker"].Status)
+	}
+	if _, found, _ := SavedDesiredCount(services(t, "test")["worker"]); found {
+		t.Error("a desired count was saved for a service without tasks")
+	}
+}
+
+func TestUnpark(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	collect(t, Park, "test")
+	results := collect(t, Unpark, "test")
+
+	for name, service := range services(t, "test") {
+		result := results[name]
+		if result.Status != StatusUnparked || result.Before != 0 || result.After != 2 {
+			t.Errorf("got result %+v for %s, want unparked from 0 to 2", result, name)
+		}
+		if service.DesiredCount != 2 {
+			t.Errorf("got desired count %d for %s, want 2", service.DesiredCount, name)
+		}
+		if _, found, _ := SavedDesiredCount(service); found {
+			t.Errorf("the saved desired count of %s was not removed", name)
+		}
+	}
+
+	limits, err := aws.FetchServiceScalingLimits("test", "api")
+	if err != nil {
+		t.Fatalf("failed to read the limits: %v", err)
+	}
+	if limits == nil || limits.Min != 2 {
+		t.Errorf("got limits %+v for an unparked service, want min 2", limits)
+	}
+	if results["api"].Message != "auto scaling minimum capacity restored to 2" {
+		t.Errorf("got message %q for api", results["api"].Message)
+	}
+	if _, found, _ := SavedMinCapacity(services(t, "test")["api"]); found {
+		t.Error("the saved minimum capacity of api was not removed")
+	}
+
+	for name, result := range collect(t, Unpark, "test") {
+		if result.Status != StatusSkipped {
+			t.Errorf("got status %s for %s when not parked, want skipped", result.Status, name)
+		}
+	}
+}
+
+func TestParkUnknownCluster(t *testing.T) {
+	fake.NewBackend(fake.DemoFixtures()).Install()
+
+	if err := Park("missing", func(Result) {}); err == nil {
+		t.Error("parking a missing cluster succeeded")
+	}
+}
+
+func TestSavedDesiredCount(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      int32
+		wantError bool
+	}{
+		{value: "3", want: 3},
+		{value: "0", want: 0},
+		{value: "-1", wantError: true},
+		{value: "three", wantError: true},
+	}
+
+	for _, test := range tests {
+		service := types.Service{Tags: []types.Tag{{Key: awssdk.String(DesiredCountTag), Value: awssdk.String(test.value)}}}
+
+		got, found, err := SavedDesiredCount(service)
+		if !found || (err != nil) != test.wantError || got != test.want {
+			t.Errorf("SavedDesiredCount(%s) = %d, %t, %v", test.value, got, found, err)
+		}
+	}
+
+	if _, found, _ := SavedDesiredCount(types.Service{}); found {
+		t.Error("found a saved desired count on a service without tags")
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"syscall"
 
 	"github.com/bsek/s9k/internal/aws"
+	"github.com/bsek/s9k/internal/config"
 	"github.com/bsek/s9k/internal/ui"
+	"github.com/bsek/s9k/internal/utils"
 	"github.com/creack/pty"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/term"
@@ -38,14 +40,19 @@ func NewShellPage(taskArn, containerName, clusterArn string) {
 		return
 	}
 
-	ui.App.TviewApp.Suspend(runSessionManangerPlugin(json))
+	region := config.ForCluster(utils.RemoveAllBeforeLastChar("/", &clusterArn)).SessionManagerRegion
+	if region == "" {
+		region = aws.CurrentRegion()
+	}
+
+	ui.App.TviewApp.Suspend(runSessionManangerPlugin(json, region))
 }
 
-func runSessionManangerPlugin(json []byte) func() {
+func runSessionManangerPlugin(json []byte, region string) func() {
 	return func() {
 		clearScreen()
 
-		cmd := exec.Command("session-manager-plugin", string(json), "eu-north-1", "StartSession")
+		cmd := exec.Command("session-manager-plugin", string(json), region, "StartSession")
 
 		ptmx, err := pty.Start(cmd)
 		if err != nil {
@@ -0,0 +1,103 @@
+// Package testevents keeps a local library of payloads for invoking lambda functions. Each event is a JSON file
+// in a directory per function, <config dir>/events/<function>/<name>.json
+package testevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bsek/s9k/internal/config"
+)
+
+const extension = ".json"
+
+// errNoConfigDir is returned when there is no configuration directory to keep the events in, e.g. when the home
+// directory is unknown
+var errNoConfigDir = errors.New("no configuration directory for saved events, set XDG_CONFIG_HOME or HOME")
+
+// Dir returns the directory holding the saved events of a function
+func Dir(functionName string) (string, error) {
+	dir := config.Dir()
+	if dir == "" {
+		return "", errNoConfigDir
+	}
+
+	return filepath.Join(dir, "events", functionName), nil
+}
+
+// List returns the names of the saved events of a function, sorted by name. A function without saved events
+// has no directory, which is not an error
+func List(functionName string) ([]string, error) {
+	dir, err := Dir(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), extension) {
+			names = append(names, strings.TrimSuffix(entry.Name(), extension))
+		}
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// Load returns the payload of a saved event
+func Load(functionName, name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	dir, err := Dir(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filepath.Join(dir, name+extension))
+}
+
+// Save stores the payload as an event of the function, replacing an event with the same name. The payload must
+// be valid JSON
+func Save(functionName, name string, payload []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	if !json.Valid(payload) {
+		return fmt.Errorf("the payload of event %s is not valid JSON", name)
+	}
+
+	dir, err := Dir(functionName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name+extension), payload, 0o600)
+}
+
+// validateName makes sure the name of an event is a plain file name
+func validateName(name string) error {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%q is not a valid event name", name)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package testevents
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	names, err := List("orders-handler")
+	if err != nil || len(names) != 0 {
+		t.Fatalf("got events %v and error %v for a function without events, want none", names, err)
+	}
+
+	for _, name := range []string{"order-created", "empty"} {
+		if err := Save("orders-handler", name, []byte(`{"orderId": 42}`)); err != nil {
+			t.Fatalf("Save(%s) failed: %v", name, err)
+		}
+	}
+	if err := Save("orders-handler", "empty", []byte(`{}`)); err != nil {
+		t.Fatalf("replacing an event failed: %v", err)
+	}
+
+	names, err = List("orders-handler")
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if want := []string{"empty", "order-created"}; !slices.Equal(names, want) {
+		t.Errorf("got events %v, want %v", names, want)
+	}
+
+	payload, err := Load("orders-handler", "empty")
+	if err != nil || string(payload) != `{}` {
+		t.Errorf("got payload %s and error %v, want the replaced event", payload, err)
+	}
+
+	if names, _ := List("email-sender"); len(names) != 0 {
+		t.Errorf("got events %v of another function", names)
+	}
+}
+
+func TestListIgnoresOtherFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := Save("orders-handler", "order-created", []byte(`{}`)); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	dir, err := Dir("orders-handler")
+	if err != nil {
+		t.Fatalf("Dir() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "old.json"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := List("orders-handler")
+	if err != nil || !slices.Equal(names, []string{"order-created"}) {
+		t.Errorf("got events %v and error %v, want only order-created", names, err)
+	}
+}
+
+func TestSaveInvalidEvent(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := Save("orders-handler", "broken", []byte(`{"orderId": `)); err == nil {
+		t.Error("saving an event that is not JSON succeeded")
+	}
+
+	for _, name := range []string{"", ".hidden", "../escape", "nested/event"} {
+		if err := Save("orders-handler", name, []byte(`{}`)); err == nil {
+			t.Errorf("saving an event named %q succeeded", name)
+		}
+		if _, err := Load("orders-handler", name); err == nil {
+			t.Errorf("loading an event named %q succeeded", name)
+		}
+	}
+}
+
+func TestWithoutConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "")
+
+	if err := Save("orders-handler", "order-created", []byte(`{}`)); !errors.Is(err, errNoConfigDir) {
+		t.Errorf("got error %v from Save(), want no configuration directory", err)
+	}
+	if _, err := List("orders-handler"); !errors.Is(err, errNoConfigDir) {
+		t.Errorf("got error %v from List(), want no configuration directory", err)
+	}
+	if _, err := Load("orders-handler", "order-created"); !errors.Is(err, errNoConfigDir) {
+		t.Errorf("got error %v from Load(), want no configuration directory", err)
+	}
+}
@@ -17,7 +17,7 @@ func NewApplication(accountData *data.AccountData) *Application {
 		Layout:      tview.NewFlex(),
 		Content:     tview.NewPages(),
 		ContentMap:  make(map[string]ContentPage, 0),
-		HeaderBar:   NewHeader(accountData.AccountId, accountData.ClusterName),
+		HeaderBar:   NewHeader(accountData.AccountId, accountData.ClusterName, accountData.Profile, accountData.Region),
 		AccountData: accountData,
 	}
 }
@@ -57,14 +57,65 @@ func (a *Application) ShowPage(selectedPage ContentPage) {
 }
 
 func (a *Application) updateData() {
-	a.AccountData.Refresh()
+	if err := a.AccountData.Refresh(); err != nil {
+		log.Error().Err(err).Msg("Failed to update data")
+		CreateMessageBox("Failed to update data, check log file")
+		return
+	}
 	a.getCurrentDisplayedContentPage().Render(a.AccountData)
 	a.HeaderBar.UpdateRefreshTime(a.AccountData.Refreshed)
 	a.HeaderBar.Render(a.ContentMap)
 }
 
+// SwitchCluster rebuilds the account data for the given ECS cluster and re-renders all registered pages.
+// Pages that are not persistent belong to the previous cluster and are removed. If the data of the cluster can not
+// be read, the current account data is kept
+func (a *Application) SwitchCluster(clusterName string) error {
+	accountData := data.NewAccountData(clusterName, a.AccountData.AccountId)
+	if err := accountData.Refresh(); err != nil {
+		return err
+	}
+
+	a.SetAccountData(accountData)
+	return nil
+}
+
+// SetAccountData replaces the account data used by the application, updates the header and re-renders
+// every registered page
+func (a *Application) SetAccountData(accountData *data.AccountData) {
+	log.Debug().Msgf("Switching to cluster %s in account %s", accountData.ClusterName, accountData.AccountId)
+
+	a.AccountData = accountData
+	a.HeaderBar.AccountId = accountData.AccountId
+	a.HeaderBar.ClusterName = accountData.ClusterName
+	a.HeaderBar.Profile = accountData.Profile
+	a.HeaderBar.Region = accountData.Region
+
+	for _, page := range a.ContentMap {
+		if page.IsPersistent() {
+			page.Render(accountData)
+		} else {
+			a.RemoveContent(page)
+		}
+	}
+
+	a.HeaderBar.UpdateRefreshTime(accountData.Refreshed)
+	a.HeaderBar.Render(a.ContentMap)
+}
+
 // Handle a user input event
 func (a *Application) handleAppInput(event *tcell.EventKey) *tcell.EventKey {
+	// keys typed into a text field are not shortcuts
+	switch a.TviewApp.GetFocus().(type) {
+	case *tview.InputField, *tview.TextArea:
+		return event
+	}
+
+	// keys go to an open dialog, it is in front of the content pages
+	if a.getCurrentDisplayedContentPage() == nil {
+		return event
+	}
+
 	if event.Key() == tcell.KeyRune {
 		key := event.Rune()
 
@@ -18,7 +18,7 @@ const logo = ` ▄▄▄▄▄▄▄ ▄▄▄▄▄▄▄ ▄▄▄   ▄
  ▄▄▄▄▄█ █   █   █    ▄  █
 █▄▄▄▄▄▄▄█   █▄▄▄█▄▄▄█ █▄█`
 
-func NewHeader(accountId, clusterName string) *Header {
+func NewHeader(accountId, clusterName, profile, region string) *Header {
 	layout := tview.NewFlex().
 		SetDirection(tview.FlexColumn)
 
@@ -29,6 +29,8 @@ func NewHeader(accountId, clusterName string) *Header {
 		Layout:      layout,
 		AccountId:   accountId,
 		ClusterName: clusterName,
+		Profile:     profile,
+		Region:      region,
 	}
 }
 
@@ -73,7 +75,7 @@ func (h *Header) Render(contentMap map[string]ContentPage) {
 
 	fmt.Fprintln(aw, fmt.Sprintf("[darkolivegreen::b]Account id: [-::]%s", h.AccountId))
 	fmt.Fprintln(aw, fmt.Sprintf("[darkolivegreen::b]Cluster name: [-::]%s", h.ClusterName))
-	fmt.Fprintln(aw, "")
+	fmt.Fprintln(aw, fmt.Sprintf("[darkolivegreen::b]Profile: [-::]%s [darkolivegreen::b]Region: [-::]%s", h.Profile, h.Region))
 	fmt.Fprintln(aw, "[white::b]u[darkcyan::-] Update data")
 	fmt.Fprintln(aw, "[white::b]q[darkcyan::-] Quit application")
 	fmt.Fprintln(aw, "")
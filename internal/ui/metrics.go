@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+// MetricWindow is a time window metrics can be shown for, selected with its key
+type MetricWindow struct {
+	Key      rune
+	Label    string
+	Duration time.Duration
+}
+
+// MetricWindows are the time windows of the metric views, the first is the default
+var MetricWindows = []MetricWindow{
+	{'1', "last hour", time.Hour},
+	{'2', "last 6 hours", 6 * time.Hour},
+	{'3', "last 24 hours", 24 * time.Hour},
+}
+
+// MetricWindowForKey returns the window selected with the key, if any
+func MetricWindowForKey(key rune) (MetricWindow, bool) {
+	for _, window := range MetricWindows {
+		if window.Key == key {
+			return window, true
+		}
+	}
+	return MetricWindow{}, false
+}
+
+// MetricsView shows metric series as sparklines in a grid, each with its label and a summary above it
+type MetricsView struct {
+	*tview.Flex
+	columns    int
+	colors     map[string]tcell.Color
+	summaries  []*tview.TextView
+	sparklines []*Sparkline
+}
+
+// NewMetricsView returns a view with the given number of sparklines on each row
+func NewMetricsView(columns int) *MetricsView {
+	return &MetricsView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		columns: max(columns, 1),
+		colors:  map[string]tcell.Color{},
+	}
+}
+
+// SetColor sets the color of the sparkline of the series with the label
+func (m *MetricsView) SetColor(label string, color tcell.Color) *MetricsView {
+	m.colors[label] = color
+	return m
+}
+
+// SetSeries shows the series in the order given. The grid is rebuilt when the number of series changes
+func (m *MetricsView) SetSeries(series []aws.MetricSeries) {
+	if len(m.sparklines) != len(series) {
+		m.build(series)
+	}
+
+	for i, s := range series {
+		m.summaries[i].SetText(fmt.Sprintf("[white::b]%s[-::-]  %s", s.Label, SummarizeMetric(s)))
+		m.sparklines[i].SetValues(s.Values)
+	}
+}
+
+func (m *MetricsView) build(series []aws.MetricSeries) {
+	m.Clear()
+	m.summaries = nil
+	m.sparklines = nil
+
+	var row *tview.Flex
+	for i, s := range series {
+		if i%m.columns == 0 {
+			row = tview.NewFlex()
+			m.AddItem(row, 0, 1, false)
+		}
+
+		summary := tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+		sparkline := NewSparkline()
+		if color, found := m.colors[s.Label]; found {
+			sparkline.SetColor(color)
+		}
+
+		cell := tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(summary, 1, 0, false).
+			AddItem(sparkline, 0, 1, false)
+		cell.SetBorderPadding(0, 0, 1, 1)
+
+		row.AddItem(cell, 0, 1, false)
+
+		m.summaries = append(m.summaries, summary)
+		m.sparklines = append(m.sparklines, sparkline)
+	}
+
+	// the last row is filled up, so all sparklines have the same width
+	for i := len(series); i%m.columns != 0; i++ {
+		row.AddItem(nil, 0, 1, false)
+	}
+}
+
+// SummarizeMetric describes a series with its total for sums, and its latest and largest value for other
+// statistics
+func SummarizeMetric(series aws.MetricSeries) string {
+	if series.Stat == "Sum" {
+		return fmt.Sprintf("total %s", FormatMetric(sumOf(series.Values), series.Unit))
+	}
+
+	latest, largest := 0.0, 0.0
+	if len(series.Values) > 0 {
+		latest = series.Values[len(series.Values)-1]
+	}
+	for _, value := range series.Values {
+		largest = max(largest, value)
+	}
+
+	return fmt.Sprintf("latest %s, max %s", FormatMetric(latest, series.Unit), FormatMetric(largest, series.Unit))
+}
+
+// FormatMetric formats a metric value with its unit, without decimals except for small fractions
+func FormatMetric(value float64, unit string) string {
+	text := fmt.Sprintf("%.0f", value)
+	if value != 0 && value < 10 && value != float64(int(value)) {
+		text = fmt.Sprintf("%.2f", value)
+	}
+
+	switch unit {
+	case "":
+		return text
+	case "%":
+		return text + unit
+	}
+	return text + " " + unit
+}
+
+func sumOf(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/bsek/s9k/internal/aws"
+)
+
+func TestFormatMetric(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  string
+	}{
+		{value: 0, unit: "", want: "0"},
+		{value: 42, unit: "", want: "42"},
+		{value: 1250.4, unit: "ms", want: "1250 ms"},
+		{value: 2.5, unit: "%", want: "2.50%"},
+		{value: 0.25, unit: "ms", want: "0.25 ms"},
+		{value: 7, unit: "%", want: "7%"},
+	}
+
+	for _, test := range tests {
+		if got := FormatMetric(test.value, test.unit); got != test.want {
+			t.Errorf("FormatMetric(%v, %q) = %q, want %q", test.value, test.unit, got, test.want)
+		}
+	}
+}
+
+func TestSummarizeMetric(t *testing.T) {
+	tests := []struct {
+		series aws.MetricSeries
+		want   string
+	}{
+		{series: aws.MetricSeries{Stat: "Sum", Values: []float64{1, 2, 3}}, want: "total 6"},
+		{series: aws.MetricSeries{Stat: "Average", Unit: "ms", Values: []float64{120, 300, 80}}, want: "latest 80 ms, max 300 ms"},
+		{series: aws.MetricSeries{Stat: "Maximum", Unit: "%"}, want: "latest 0%, max 0%"},
+	}
+
+	for _, test := range tests {
+		if got := SummarizeMetric(test.series); got != test.want {
+			t.Errorf("SummarizeMetric(%+v) = %q, want %q", test.series, got, test.want)
+		}
+	}
+}
+
+func TestMetricWindowForKey(t *testing.T) {
+	if window, found := MetricWindowForKey('2'); !found || window.Label != "last 6 hours" {
+		t.Errorf("got window %+v, want the last 6 hours", window)
+	}
+	if _, found := MetricWindowForKey('9'); found {
+		t.Error("found a window for an unknown key")
+	}
+}
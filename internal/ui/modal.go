@@ -41,7 +41,8 @@ func CreateMessageBox(content string) {
 			App.Content.RemovePage(MESSAGE_BOX)
 		})
 
-	App.Content.AddAndSwitchToPage(MESSAGE_BOX, modal, true)
+	// shown on top of the visible pages, closing it returns to the page or dialog it was opened from
+	App.Content.AddPage(MESSAGE_BOX, modal, true, true)
 }
 
 // CreateConfirmBox creates a modal message box using tview's native modal element. Two buttons are added (yes and no)
@@ -63,5 +64,5 @@ func CreateConfirmBox(content string, yes, no func()) {
 			}
 		})
 
-	App.Content.AddAndSwitchToPage(CONFIRM_BOX, modal, true)
+	App.Content.AddPage(CONFIRM_BOX, modal, true, true)
 }
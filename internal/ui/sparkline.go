@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// sparkBlocks are the block characters for one to eight eighths of a cell
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline draws a series of values as bars of block characters, scaled so the largest value fills the height.
+// The newest value is drawn at the right edge. When there are more values than columns, each column shows the
+// largest of the values it covers, so spikes are not lost
+type Sparkline struct {
+	*tview.Box
+	values []float64
+	color  tcell.Color
+}
+
+func NewSparkline() *Sparkline {
+	return &Sparkline{
+		Box:   tview.NewBox(),
+		color: tcell.ColorDarkCyan,
+	}
+}
+
+// SetValues sets the values to draw, oldest first
+func (s *Sparkline) SetValues(values []float64) *Sparkline {
+	s.values = values
+	return s
+}
+
+// SetColor sets the color of the bars
+func (s *Sparkline) SetColor(color tcell.Color) *Sparkline {
+	s.color = color
+	return s
+}
+
+func (s *Sparkline) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	columns := resample(s.values, width)
+
+	maximum := 0.0
+	for _, value := range columns {
+		maximum = max(maximum, value)
+	}
+
+	style := tcell.StyleDefault.Foreground(s.color).Background(s.GetBackgroundColor())
+	offset := x + width - len(columns)
+
+	for i, value := range columns {
+		eighths := 0
+		if maximum > 0 {
+			eighths = int(value / maximum * float64(height*8))
+		}
+		// values above zero are always visible
+		if value > 0 && eighths == 0 {
+			eighths = 1
+		}
+
+		for row := 0; row < height && eighths > row*8; row++ {
+			block := sparkBlocks[min(eighths-row*8, 8)-1]
+			screen.SetContent(offset+i, y+height-1-row, block, nil, style)
+		}
+	}
+}
+
+// resample returns at most width values. Values are repeated to fill the width when there are few of them, and
+// otherwise each column has the largest of the values it covers
+func resample(values []float64, width int) []float64 {
+	if len(values) == 0 {
+		return values
+	}
+
+	if len(values) <= width {
+		repeat := width / len(values)
+		columns := make([]float64, 0, repeat*len(values))
+		for _, value := range values {
+			for range repeat {
+				columns = append(columns, value)
+			}
+		}
+		return columns
+	}
+
+	columns := make([]float64, width)
+	for i := range columns {
+		from := i * len(values) / width
+		to := (i + 1) * len(values) / width
+
+		columns[i] = values[from]
+		for _, value := range values[from:to] {
+			columns[i] = max(columns[i], value)
+		}
+	}
+
+	return columns
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   []float64
+	}{
+		{[]float64{}, 10, []float64{}},
+		{[]float64{1, 2, 3}, 3, []float64{1, 2, 3}},
+		{[]float64{1, 2, 3}, 7, []float64{1, 1, 2, 2, 3, 3}},
+		{[]float64{1, 5, 2, 0, 3, 4}, 3, []float64{5, 2, 4}},
+		{[]float64{1, 5, 2, 0, 3}, 2, []float64{5, 3}},
+		{[]float64{0, 0, 7, 0}, 1, []float64{7}},
+	}
+
+	for _, tt := range tests {
+		got := resample(tt.values, tt.width)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("resample(%v, %d) = %v, want %v", tt.values, tt.width, got, tt.want)
+		}
+		if len(got) > tt.width {
+			t.Errorf("resample(%v, %d) returned %d values", tt.values, tt.width, len(got))
+		}
+	}
+}
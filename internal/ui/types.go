@@ -23,6 +23,8 @@ type Header struct {
 	Layout      *tview.Flex
 	AccountId   string
 	ClusterName string
+	Profile     string
+	Region      string
 }
 
 type ContentPage interface {
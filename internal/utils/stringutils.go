@@ -2,7 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"math"
 	"strconv"
 	"strings"
 
@@ -63,21 +62,3 @@ func TakeLeft(s string, max int) string {
 	}
 	return result
 }
-
-// BuildAsciiMeterCurrentTotal builds a one-line meter using the amount and total values limited to the given width
-func BuildAsciiMeterCurrentTotal(portion uint32, total uint32, width int) string {
-	const fullChar = "█"
-	const emptyChar = "▒"
-
-	full := 0
-	if total > 0 {
-		ratio := float64(portion) / float64(total)
-		ratio = math.Max(0, math.Min(1.0, ratio))
-		full = int(math.Round(ratio * float64(width)))
-	}
-
-	return strings.Join([]string{
-		strings.Repeat(fullChar, full),
-		strings.Repeat(emptyChar, width-full),
-	}, "")
-}